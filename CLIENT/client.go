@@ -1,531 +1,295 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"os"
-	"os/exec"
-	"runtime"
-	"strconv"
-	"strings"
+	"path/filepath"
 	"time"
 
-	"github.com/shirou/gopsutil/cpu"
-	"github.com/shirou/gopsutil/mem"
-	"github.com/shirou/gopsutil/net"
+	"colector"
 )
 
-// Structura pentru informatii despre sistemul de operare
-type OSInfo struct {
-	Nume           string `json:"nume"`
-	Versiune       string `json:"versiune"`
-	Arhitectura    string `json:"arhitectura"`
-	DataInstalarii string `json:"data_instalarii"`
-	Licenta        string `json:"licenta"`
-}
-
-// Functie pentru a obtine informatii despre sistemul de operare
-func getOSInfo() (*OSInfo, error) {
-	osInfo := &OSInfo{}
-
-	// Obtine numele si versiunea sistemului de operare
-	switch runtime.GOOS {
-	case "windows":
-		cmd := exec.Command("cmd", "/c", "ver")
-		out, err := cmd.Output()
-		if err != nil {
-			return nil, fmt.Errorf("eroare la executarea comenzii 'ver': %w", err)
-		}
-		osInfo.Nume = strings.TrimSpace(string(out))
-
-		// Obtine arhitectura sistemului de operare
-		osInfo.Arhitectura = runtime.GOARCH
+// Versiunea agentului, trimisă la server împreună cu datele
+const agentVersion = "1.1.0"
 
-		// Obtine data instalarii sistemului de operare (pentru Windows)
-		cmd = exec.Command("cmd", "/c", "wmic os get InstallDate /VALUE")
-		out, err = cmd.Output()
-		if err != nil {
-			return nil, fmt.Errorf("eroare la executarea comenzii 'wmic os get InstallDate': %w", err)
-		}
-		lines := strings.Split(strings.TrimSpace(string(out)), "=")
-		if len(lines) > 1 {
-			dateString := strings.TrimSpace(lines[1])
-
-			// Soluția 1: Ajustarea formatului datei
-			// dataInstalare, err := time.Parse("20060102150405.000000-0700", dateString) // Formatul corect pentru fusul orar
-			// if err != nil {
-			// 	return nil, fmt.Errorf("eroare la parsarea datei de instalare: %w", err)
-			// }
-
-			// Soluția 2: Eliminarea fusului orar
-			if strings.Contains(dateString, "+") {
-				parts := strings.Split(dateString, "+")
-				dateString = parts[0] // Păstrează doar partea de dată
-			}
-
-			// Parsare DataInstalarii în format time.Time (fără fus orar)
-			dataInstalare, err := time.Parse("20060102150405.000000", dateString)
-			if err != nil {
-				return nil, fmt.Errorf("eroare la parsarea datei de instalare: %w", err)
-			}
+// Structura pentru răspunsul serverului la datele de inventar
+type inventoryResponse struct {
+	HashuriCunoscute map[string]string `json:"hashuri_cunoscute"`
+}
 
-			// Formatare DataInstalarii în ISO 8601
-			osInfo.DataInstalarii = dataInstalare.Format("2006-01-02 15:04:05")
-		}
+// Funcție pentru a afișa informațiile live despre sistem
+func printLiveInfo(liveInfo *colector.LiveSystemInfo) {
+	fmt.Printf("--------------------\n")
+	fmt.Printf("Informații Live:\n")
+	fmt.Printf("Utilizare CPU: %.2f%%\n", liveInfo.UtilizareCPU)
+	fmt.Printf("Utilizare RAM: %.2f%%\n", liveInfo.UtilizareRAM)
+	fmt.Printf("Trafic Retea Trimis: %d\n", liveInfo.TraficTrimis)
+	fmt.Printf("Trafic Retea Receptionat: %d\n", liveInfo.TraficReceptionat)
+}
 
-		// Obtine licenta sistemului de operare (daca este cazul)
-		// Implementati aici logica specifica pentru licenta Windows
-		osInfo.Licenta = "N/A"
-
-	case "linux":
-		// Implementati logica pentru Linux
-		osInfo.Nume = "Linux" // Exemplu simplificat pentru numele sistemului de operare
-		osInfo.Versiune = "N/A"
-		osInfo.Arhitectura = runtime.GOARCH
-		osInfo.DataInstalarii = "N/A"
-		osInfo.Licenta = "N/A"
-
-	case "darwin":
-		// Implementati logica pentru macOS
-		cmd := exec.Command("sw_vers", "-productName")
-		out, err := cmd.Output()
-		if err != nil {
-			return nil, fmt.Errorf("eroare la executarea comenzii 'sw_vers -productName': %w", err)
+// Funcție care rulează colectoarele date și afișează erorile și datele live
+func collectResults(ctx context.Context, collectors []colector.Collector) []colector.Result {
+	results := colector.Collect(ctx, collectors)
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Printf("Eroare la obținerea informațiilor: %v\n", result.Err)
+			continue
 		}
-		osInfo.Nume = strings.TrimSpace(string(out))
-
-		cmd = exec.Command("sw_vers", "-productVersion")
-		out, err = cmd.Output()
-		if err != nil {
-			return nil, fmt.Errorf("eroare la executarea comenzii 'sw_vers -productVersion': %w", err)
+		if liveInfo, ok := result.Data.(*colector.LiveSystemInfo); ok {
+			printLiveInfo(liveInfo)
 		}
-		osInfo.Versiune = strings.TrimSpace(string(out))
-
-		osInfo.Arhitectura = runtime.GOARCH
-		osInfo.DataInstalarii = "N/A" // Exemplu simplificat pentru macOS
-		osInfo.Licenta = "N/A"
-
-	default:
-		return nil, fmt.Errorf("sistem de operare neacceptat: %s", runtime.GOOS)
 	}
-
-	return osInfo, nil
-}
-
-// Structura pentru informatii despre hardware
-type HardwareInfo struct {
-	Procesor      string `json:"procesor"`
-	Nuclee        int    `json:"nuclee"`
-	FireExecutie  int    `json:"fire_executie"`
-	Frecventa     string `json:"frecventa"`
-	MemorieRAM    string `json:"memorie_ram"`
-	TipStocare    string `json:"tip_stocare"`
-	CapacitateHDD string `json:"capacitate_hdd"`
-	PlacaDeBaza   string `json:"placa_de_baza"`
-	PlacaVideo    string `json:"placa_video"`
+	return results
 }
 
-// Functie pentru a obtine informatii despre hardware
-func getHardwareInfo() (*HardwareInfo, error) {
-	hardwareInfo := &HardwareInfo{}
-
-	// Obtine informatii despre procesor
-	cmd := exec.Command("cmd", "/c", "wmic cpu get Name,NumberOfCores,NumberOfLogicalProcessors,MaxClockSpeed /FORMAT:LIST")
-	out, err := cmd.Output()
+// Funcție care pregătește plicul cu date pentru server; datele sunt
+// salvate și local, în <dataDir>/<tip>_data.json
+// Numărul de secvență este al datelor colectate, nu al încercării de trimitere:
+// inventarul retrimis păstrează numărul primit la colectare
+func preparePayload(envelope *colector.Envelope, identity *colector.StationIdentity, secventa int64, dataDir string) ([]byte, error) {
+	envelope.VersiuneAgent = agentVersion
+	envelope.Statie = identity
+	envelope.Secventa = secventa
+
+	// Serializează datele în format JSON
+	jsonData, err := json.MarshalIndent(envelope, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic cpu get ...': %w", err)
-	}
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "=") {
-			fields := strings.Split(line, "=")
-			switch strings.TrimSpace(fields[0]) {
-			case "Name":
-				hardwareInfo.Procesor = strings.TrimSpace(fields[1])
-			case "NumberOfCores":
-				hardwareInfo.Nuclee, _ = strconv.Atoi(strings.TrimSpace(fields[1]))
-			case "NumberOfLogicalProcessors":
-				hardwareInfo.FireExecutie, _ = strconv.Atoi(strings.TrimSpace(fields[1]))
-			case "MaxClockSpeed":
-				hardwareInfo.Frecventa = strings.TrimSpace(fields[1]) + " MHz"
-			}
-		}
+		return nil, fmt.Errorf("eroare la serializarea JSON: %w", err)
 	}
 
-	// Obtine informatii despre memoria RAM
-	cmd = exec.Command("cmd", "/c", "wmic memorychip get Capacity /FORMAT:LIST")
-	out, err = cmd.Output()
+	// Scrie datele JSON în fișier
+	err = os.WriteFile(filepath.Join(dataDir, envelope.Tip+"_data.json"), jsonData, 0644)
 	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic memorychip get Capacity': %w", err)
-	}
-	lines = strings.Split(strings.TrimSpace(string(out)), "\n")
-	var totalRAM uint64
-	for _, line := range lines {
-		if strings.Contains(line, "=") {
-			fields := strings.Split(line, "=")
-			if len(fields) > 1 {
-				capacity, _ := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
-				totalRAM += capacity
-			}
-		}
+		return nil, fmt.Errorf("eroare la scrierea în fișierul JSON: %w", err)
 	}
-	hardwareInfo.MemorieRAM = fmt.Sprintf("%d GB", totalRAM/(1024*1024*1024))
 
-	// Obtine informatii despre stocare (inclusiv SSD-uri NVMe)
-	cmd = exec.Command("powershell", "-Command", "Get-PhysicalDisk | Where-Object {$_.BusType -eq 'NVMe'} | ConvertTo-Json")
-	out, err = cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii PowerShell: %w", err)
-	}
+	return jsonData, nil
+}
 
-	//  Verificam dacă rezultatul este un array JSON sau un singur obiect
-	var disks []map[string]interface{}
-	if strings.HasPrefix(strings.TrimSpace(string(out)), "[") {
-		// Este un array JSON
-		err = json.Unmarshal(out, &disks)
-		if err != nil {
-			return nil, fmt.Errorf("eroare la parsarea JSON (array): %w", err)
-		}
-	} else {
-		// Este un singur obiect JSON
-		var disk map[string]interface{}
-		err = json.Unmarshal(out, &disk)
-		if err != nil {
-			return nil, fmt.Errorf("eroare la parsarea JSON (obiect): %w", err)
-		}
-		disks = append(disks, disk) // Adăugăm obiectul la un slice
-	}
-	if len(disks) > 0 {
-		// Extragem informatiile pentru primul disc gasit
-		disk := disks[0]
-		hardwareInfo.TipStocare = disk["MediaType"].(string)
-
-		// Convertim dimensiunea discului din bytes in GB
-		sizeBytes := int64(disk["Size"].(float64))
-		hardwareInfo.CapacitateHDD = fmt.Sprintf("%d GB", sizeBytes/(1024*1024*1024))
-	} else {
-		hardwareInfo.TipStocare = "N/A"
-		hardwareInfo.CapacitateHDD = "N/A"
+// Funcție care trimite inventarul la server și reține hash-urile confirmate
+func sendInventory(t *transport, inventory *colector.InventoryState, identity *colector.StationIdentity, secventa int64, dataDir string) error {
+	jsonData, err := preparePayload(inventory.Payload(), identity, secventa, dataDir)
+	if err != nil {
+		return err
 	}
 
-	// Obtine informatii despre placa de baza
-	cmd = exec.Command("cmd", "/c", "wmic baseboard get Manufacturer,Product /FORMAT:LIST")
-	out, err = cmd.Output()
+	body, err := t.sendJSONToServer(jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic baseboard get ...': %w", err)
-	}
-	lines = strings.Split(strings.TrimSpace(string(out)), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "=") {
-			fields := strings.Split(line, "=")
-			switch strings.TrimSpace(fields[0]) {
-			case "Manufacturer":
-				hardwareInfo.PlacaDeBaza = strings.TrimSpace(fields[1])
-			case "Product":
-				hardwareInfo.PlacaDeBaza += " " + strings.TrimSpace(fields[1])
-			}
-		}
-		break // Se obtin doar informatiile despre prima placa de baza
+		return err
 	}
 
-	// Obtine informatii despre placa video
-	cmd = exec.Command("cmd", "/c", "wmic path win32_videocontroller get Name /FORMAT:LIST")
-	out, err = cmd.Output()
+	var response inventoryResponse
+	err = json.Unmarshal(body, &response)
 	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic path win32_videocontroller get Name': %w", err)
-	}
-	lines = strings.Split(strings.TrimSpace(string(out)), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "=") {
-			fields := strings.Split(line, "=")
-			if len(fields) > 1 {
-				hardwareInfo.PlacaVideo = strings.TrimSpace(fields[1])
-			}
-		}
-		break // Se obtin doar informatiile despre prima placa video
+		return fmt.Errorf("eroare la parsarea răspunsului serverului: %w", err)
 	}
+	inventory.Acknowledge(response.HashuriCunoscute)
 
-	return hardwareInfo, nil
-}
-
-// Structura pentru informatii despre software (programe instalate)
-type SoftwareInfo struct {
-	ProgrameInstalate []ProgramInfo `json:"programe_instalate"`
-}
-
-// Structura pentru informatii despre un program instalat
-type ProgramInfo struct {
-	Nume          string `json:"nume"`
-	Versiune      string `json:"versiune"`
-	Producator    string `json:"producator"`
-	DataInstalare string `json:"data_instalare"`
-	Licenta       string `json:"licenta"`
-	// Alte informatii despre program
+	return nil
 }
 
-// Functie pentru a obtine informatii despre programele instalate
-func getInstalledPrograms() ([]ProgramInfo, error) {
-	var programs []ProgramInfo
-
-	// Implementati metoda pentru a obtine lista de programe instalate pe sistemul de operare
-	// Iata un exemplu simplificat pentru Windows, care utilizeaza registrele de sistem
-	// pentru a obtine informatiile necesare. Trebuie adaptata pentru diferite sisteme de operare.
-
-	// Exemplu: Windows
-	cmd := exec.Command("cmd", "/c", "wmic product get Name,Version,Vendor,InstallDate /FORMAT:LIST")
-	out, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic product get ...': %w", err)
+// Funcție care trimite eșantioanele din coada locală, câte o încercare pentru
+// fiecare, și se oprește la primul eșantion care nu poate fi trimis
+func flushSpool(t *transport, queue *spool) error {
+	sent, err := queue.flush(func(data []byte) error {
+		_, err := t.sendOnce(data)
+		return err
+	})
+	if sent > 0 {
+		fmt.Printf("Au fost trimise %d eșantioane din coada locală\n", sent)
 	}
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	var program ProgramInfo
-	for _, line := range lines {
-		if strings.Contains(line, "=") {
-			fields := strings.Split(line, "=")
-			switch strings.TrimSpace(fields[0]) {
-			case "Name":
-				program.Nume = strings.TrimSpace(fields[1])
-			case "Version":
-				program.Versiune = strings.TrimSpace(fields[1])
-			case "Vendor":
-				program.Producator = strings.TrimSpace(fields[1])
-			case "InstallDate":
-				program.DataInstalare = strings.TrimSpace(fields[1])
-			}
-		} else {
-			// Finalizam informatiile pentru un program si adaugam in lista
-			if program.Nume != "" {
-				programs = append(programs, program)
-				program = ProgramInfo{}
-			}
-		}
-	}
-	// Adaugam ultimul program (daca exista)
-	if program.Nume != "" {
-		programs = append(programs, program)
-	}
-
-	return programs, nil
+	return err
 }
 
-// Functie pentru a obtine informatii despre securitate
-func getSecurityInfo() (string, error) {
-	// Implementati metoda pentru a obtine informatii despre statusul de securitate al sistemului
-	// Informatiile pot include statusul antivirusului, firewall-ului, etc.
-
-	// Exemplu simplificat pentru Windows
-	cmd := exec.Command("cmd", "/c", "wmic /namespace:\\\\root\\SecurityCenter2 path AntiVirusProduct get displayName /FORMAT:LIST")
-	out, err := cmd.Output()
+// Funcție care trimite datele live la server
+// Dacă în coada locală există date mai vechi, acestea sunt trimise întâi; datele
+// care nu pot fi trimise sunt adăugate în coadă
+func sendLive(t *transport, queue *spool, collectedAt time.Time, jsonData []byte) error {
+	count, _, err := queue.depth()
 	if err != nil {
-		return "N/A", fmt.Errorf("eroare la executarea comenzii 'wmic /namespace:\\\\root\\SecurityCenter2 path AntiVirusProduct get displayName': %w", err)
+		return err
 	}
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "=") {
-			fields := strings.Split(line, "=")
-			if len(fields) > 1 {
-				return strings.TrimSpace(fields[1]), nil
-			}
+	if count == 0 {
+		_, err = t.sendJSONToServer(jsonData)
+		if err == nil || isRejected(err) {
+			return err
 		}
+		pushErr := queue.push(collectedAt, jsonData)
+		if pushErr != nil {
+			return fmt.Errorf("%v; %w", err, pushErr)
+		}
+		return err
 	}
-	return "N/A", nil
-}
-
-// Structura pentru informatii despre utilizator
-type UserInfo struct {
-	NumeUtilizator string `json:"nume_utilizator"`
-	GrupUtilizator string `json:"grup_utilizator"`
-	// Alte informatii despre utilizator
-}
-
-// Functie pentru a obtine informatii despre utilizatorul curent
-func getCurrentUserInfo() (*UserInfo, error) {
-	userInfo := &UserInfo{}
-
-	// Implementati metoda pentru a obtine informatii despre utilizatorul curent
-	// Aceasta poate include numele utilizatorului, grupul utilizatorului, etc.
 
-	// Exemplu simplificat pentru Windows
-	cmd := exec.Command("cmd", "/c", "whoami")
-	out, err := cmd.Output()
+	// Datele noi sunt trimise după cele din coadă; cât timp coada nu se golește,
+	// serverul este încercat o singură dată pe iterație
+	err = queue.push(collectedAt, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("eroare la executarea comenzii 'whoami': %w", err)
+		return err
 	}
-	userInfo.NumeUtilizator = strings.TrimSpace(string(out))
-
-	// Pentru grupul utilizatorului, se poate face o interogare suplimentara sau folosind pachete/librarii aditionale
-
-	return userInfo, nil
-}
-
-// Structura pentru informatii live despre sistem
-type LiveSystemInfo struct {
-	UtilizareCPU      float64 `json:"utilizare_cpu"`
-	UtilizareRAM      float64 `json:"utilizare_ram"`
-	TraficTrimis      uint64  `json:"trafic_retea_bytes_trimisi"`
-	TraficReceptionat uint64  `json:"trafic_retea_bytes_primiti"`
+	return flushSpool(t, queue)
 }
 
-// Functie pentru a obtine informatii live despre sistem
-func getLiveSystemInfo() (*LiveSystemInfo, error) {
-	liveInfo := &LiveSystemInfo{}
+func main() {
+	// Înregistrarea și redarea comenzilor externe sunt doar opțiuni din linia de comandă
+	recordDir := flag.String("record", "", "directorul în care se salvează rezultatele comenzilor externe")
+	replayDir := flag.String("replay", "", "directorul din care se redau rezultatele comenzilor externe, fără a le rula")
 
-	// CPU Usage
-	cpuUsage, err := cpu.Percent(time.Second, true)
+	// Configurația: valori implicite, fișier, variabile de mediu și linia de comandă
+	cfg, err := loadAgentConfig(flag.CommandLine, os.Args[1:])
 	if err != nil {
-		return nil, fmt.Errorf("eroare la obținerea utilizării CPU: %w", err)
+		fmt.Printf("Eroare la încărcarea configurației: %v\n", err)
+		os.Exit(2)
 	}
-	liveInfo.UtilizareCPU = cpuUsage[0]
-
-	// RAM Usage
-	memInfo, err := mem.VirtualMemory()
-	if err != nil {
-		return nil, fmt.Errorf("eroare la obținerea utilizării RAM: %w", err)
+	colector.SysRoot = cfg.Colectare.SysRoot
+	colector.TargetOS = cfg.Colectare.OS
+
+	// Comenzile externe pot fi înregistrate sau redate dintr-un corpus
+	switch {
+	case *replayDir != "":
+		colector.Runner = colector.ReplayRunner{Dir: *replayDir}
+	case *recordDir != "":
+		err := os.MkdirAll(*recordDir, 0755)
+		if err != nil {
+			fmt.Printf("Eroare la crearea directorului de înregistrare: %v\n", err)
+			return
+		}
+		colector.Runner = colector.RecordingRunner{Runner: colector.Runner, Dir: *recordDir}
 	}
-	liveInfo.UtilizareRAM = memInfo.UsedPercent
 
-	// Network Usage
-	netIO, err := net.IOCounters(false)
+	ctx := context.Background()
+
+	// Directorul în care se salvează ultimele date trimise
+	err = os.MkdirAll(cfg.Fisiere.Date, 0755)
 	if err != nil {
-		return nil, fmt.Errorf("eroare la obținerea utilizării rețelei: %w", err)
+		fmt.Printf("Eroare la crearea directorului de date: %v\n", err)
+		return
 	}
-	liveInfo.TraficReceptionat = netIO[0].BytesRecv
-	liveInfo.TraficTrimis = netIO[0].BytesSent
 
-	return liveInfo, nil
-}
+	// Conexiunea la server este refolosită între iterații
+	client := newTransport(cfg.Server.URL, cfg.Server.Token, cfg.Server.Timeout, cfg.Server.Incercari)
 
-// Funcție pentru a trimite datele JSON la server
-func sendJSONToServer(data []byte, serverURL string) error {
-	req, err := http.NewRequest("POST", serverURL, bytes.NewBuffer(data))
+	// Identitatea stației, păstrată între rulări în fișierul de stare
+	identity, err := colector.LoadStationIdentity(ctx, cfg.Fisiere.Statie)
 	if err != nil {
-		return fmt.Errorf("eroare la crearea cererii: %w", err)
+		fmt.Printf("Eroare la obținerea identității stației: %v\n", err)
+		return
 	}
-	req.Header.Set("Content-Type", "application/json")
+	fmt.Printf("ID-ul stației: %s\n", identity.ID)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Numărul de secvență al datelor trimise
+	seq, err := loadSequence(cfg.Fisiere.Secventa)
 	if err != nil {
-		return fmt.Errorf("eroare la trimiterea cererii: %w", err)
+		fmt.Printf("Eroare la încărcarea numărului de secvență: %v\n", err)
+		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("serverul a returnat o eroare: %s - %s", resp.Status, string(body))
+	// Datele live care nu pot fi trimise sunt păstrate în coada locală și
+	// retrimise, în ordinea colectării, când serverul este din nou disponibil
+	queue, err := newSpool(cfg.Coada.Director, cfg.Coada.MaxMB*1024*1024, cfg.Coada.VarstaMaxima)
+	if err != nil {
+		fmt.Printf("Eroare la deschiderea cozii locale: %v\n", err)
+		return
 	}
 
-	// Afișează răspunsul serverului
-	fmt.Println("Răspuns de la server:")
-	fmt.Println(resp.Status)
-	body, _ := ioutil.ReadAll(resp.Body)
-	fmt.Println(string(body))
+	// Colectoarele folosite de agent, fiecare rulat la propriul interval:
+	// implicit, datele live la câteva secunde, inventarul o dată pe oră
+	scheduler := colector.NewScheduler(cfg.collectors())
 
-	return nil
-}
+	// Inventarul este trimis doar pe secțiuni: serverul primește hash-urile
+	// tuturor secțiunilor și datele celor pe care nu le cunoaște încă
+	// Inventarul care nu a putut fi trimis este retrimis împreună cu următoarele date live
+	inventory := colector.NewInventoryState()
+	pendingInventory := false
+	var inventorySeq int64
 
-func main() {
-	// Adresa serverului
-	serverURL := "http://localhost:8080/data" // Înlocuiți cu adresa corectă a serverului
-	// Creează fișierul JSON dacă nu există
-	fileName := "live_data.json"
-	if _, err := os.Stat(fileName); os.IsNotExist(err) {
-		_, err = os.Create(fileName)
-		if err != nil {
-			fmt.Printf("Eroare la crearea fișierului JSON: %v\n", err)
-			return
-		}
-	}
 	for {
-		// Creare structura pentru informațiile complete despre sistem
-		systemInfo := make(map[string]interface{})
-
-		// Obținere informații despre sistemul de operare, hardware, software etc.
-		osInfo, err := getOSInfo()
-		if err != nil {
-			fmt.Printf("Eroare la obținerea informațiilor despre sistemul de operare: %v\n", err)
-			// Gestionare eroare - puteți seta valori implicite sau să continuați cu precauție
-			osInfo = &OSInfo{}
-		}
-		systemInfo["sistem_de_operare"] = osInfo
-
-		hardwareInfo, err := getHardwareInfo()
-		if err != nil {
-			fmt.Printf("Eroare la obținerea informațiilor despre hardware: %v\n", err)
-			hardwareInfo = &HardwareInfo{}
-		}
-		systemInfo["hardware"] = hardwareInfo
-
-		installedPrograms, err := getInstalledPrograms()
-		if err != nil {
-			fmt.Printf("Eroare la obținerea informațiilor despre programele instalate: %v\n", err)
-			installedPrograms = []ProgramInfo{}
-		}
-		softwareInfo := &SoftwareInfo{
-			ProgrameInstalate: installedPrograms,
+		// Hostname-ul se poate schimba în timpul rulării; ID-ul rămâne același,
+		// dar inventarul este colectat din nou
+		if hostname, err := os.Hostname(); err == nil && hostname != identity.Hostname {
+			identity.Hostname = hostname
+			scheduler.Trigger(colector.TipInventar)
 		}
-		systemInfo["software"] = softwareInfo
 
-		securityInfo, err := getSecurityInfo()
-		if err != nil {
-			fmt.Printf("Eroare la obținerea informațiilor despre securitate: %v\n", err)
-			securityInfo = "N/A"
-		}
-		systemInfo["securitate"] = securityInfo
-
-		userInfo, err := getCurrentUserInfo()
-		if err != nil {
-			fmt.Printf("Eroare la obținerea informațiilor despre utilizator: %v\n", err)
-			userInfo = &UserInfo{}
-		}
-		systemInfo["utilizator"] = userInfo
-
-		// Extrage informații live
-		liveInfo, err := getLiveSystemInfo()
-		if err != nil {
-			fmt.Printf("Eroare la obținerea informațiilor live despre sistem: %v\n", err)
-			// Gestionare eroare - puteți seta valori implicite sau să continuați cu precauție
-			liveInfo = &LiveSystemInfo{}
-		} else {
-			fmt.Printf("--------------------\n")
-			fmt.Printf("Informații Live:\n")
-			fmt.Printf("Utilizare CPU: %.2f%%\n", liveInfo.UtilizareCPU)
-			fmt.Printf("Utilizare RAM: %.2f%%\n", liveInfo.UtilizareRAM)
-			fmt.Printf("Trafic Retea Trimis: %d\n", liveInfo.TraficTrimis)
-			fmt.Printf("Trafic Retea Receptionat: %d\n", liveInfo.TraficReceptionat)
+		due := scheduler.Due(time.Now())
+		_, collected := due[colector.TipInventar]
+		if collected {
+			err := inventory.Update(collectResults(ctx, due[colector.TipInventar]))
+			if err != nil {
+				fmt.Printf("Eroare la pregătirea inventarului: %v\n", err)
+			}
+			// Dacă toate colectoarele au eșuat, inventarul este trimis la următoarea colectare
+			pendingInventory = !inventory.Empty()
+			if !pendingInventory {
+				fmt.Println("Nicio secțiune a inventarului nu a putut fi colectată; inventarul nu este trimis.")
+			} else {
+				inventorySeq, err = seq.next()
+				if err != nil {
+					fmt.Printf("Eroare la pregătirea inventarului: %v\n", err)
+					pendingInventory = false
+				}
+			}
 		}
-
-		// Actualizează datele live în structura systemInfo
-		systemInfo["utilizare_cpu"] = liveInfo.UtilizareCPU
-		systemInfo["utilizare_ram"] = liveInfo.UtilizareRAM
-		systemInfo["trafic_retea_bytes_trimisi"] = liveInfo.TraficTrimis
-		systemInfo["trafic_retea_bytes_primiti"] = liveInfo.TraficReceptionat
-
-		// Serializează toate datele în format JSON
-		jsonData, err := json.MarshalIndent(systemInfo, "", "  ")
-		if err != nil {
-			fmt.Printf("Eroare la serializarea JSON: %v\n", err)
-			continue // Trece la următoarea iterație a buclei
+		// Eșantioanele din coadă au numere de secvență mai mici decât inventarul,
+		// așa că sunt trimise înaintea lui; dacă serverul nu le primește, inventarul
+		// rămâne netrimis până la următoarea iterație
+		if pendingInventory {
+			count, _, err := queue.depth()
+			if err == nil && count > 0 {
+				err = flushSpool(client, queue)
+			}
+			if err != nil {
+				fmt.Printf("Eroare la trimiterea datelor din coada locală: %v\n", err)
+			} else {
+				err = sendInventory(client, inventory, identity, inventorySeq, cfg.Fisiere.Date)
+			}
+			if isRejected(err) {
+				// Același inventar ar fi respins din nou (de exemplu, colectat prea demult);
+				// un inventar respins imediat după colectare așteaptă colectarea programată
+				fmt.Printf("Inventarul a fost respins de server: %v\n", err)
+				pendingInventory = false
+				if !collected {
+					scheduler.Trigger(colector.TipInventar)
+				}
+			} else if err != nil {
+				fmt.Printf("Eroare la trimiterea inventarului la server: %v\n", err)
+			} else {
+				fmt.Println("Inventarul a fost trimis cu succes la server.")
+				// Secțiunile pe care serverul nu le-a confirmat sunt trimise din nou
+				pendingInventory = inventory.Pending()
+			}
 		}
 
-		// Scrie datele JSON în fișier
-		err = os.WriteFile(fileName, jsonData, 0644)
-		if err != nil {
-			fmt.Printf("Eroare la scrierea în fișierul JSON: %v\n", err)
-			continue // Trece la următoarea iterație a buclei
+		if collectors, ok := due[colector.TipLive]; ok {
+			results := collectResults(ctx, collectors)
+			secventa, err := seq.next()
+			var jsonData []byte
+			if err == nil {
+				jsonData, err = preparePayload(colector.Payload(colector.TipLive, results), identity, secventa, cfg.Fisiere.Date)
+			}
+			if err != nil {
+				fmt.Printf("Eroare la pregătirea datelor live: %v\n", err)
+			} else {
+				err = sendLive(client, queue, colector.CollectedAt(results), jsonData)
+				if err != nil {
+					fmt.Printf("Eroare la trimiterea datelor la server: %v\n", err)
+				} else {
+					fmt.Println("Datele au fost trimise cu succes la server.")
+				}
+			}
 		}
 
-		// Trimite datele JSON la server
-		err = sendJSONToServer(jsonData, serverURL)
+		// Starea cozii locale
+		count, size, err := queue.depth()
 		if err != nil {
-			fmt.Printf("Eroare la trimiterea datelor la server: %v\n", err)
-		} else {
-			fmt.Println("Datele au fost trimise cu succes la server.")
+			fmt.Printf("Eroare la citirea cozii locale: %v\n", err)
+		} else if count > 0 {
+			fmt.Printf("Coada locală: %d eșantioane netrimise (%d octeți)\n", count, size)
 		}
 
-		time.Sleep(10 * time.Second)
+		time.Sleep(time.Until(scheduler.NextRun()))
 	}
 }
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"colector"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configurația agentului
+//
+// Valorile sunt citite, în ordinea priorității (ultima câștigă):
+//  1. valorile implicite de mai jos;
+//  2. fișierul YAML dat prin -config sau INVENTAR_AGENT_CONFIG;
+//  3. variabilele de mediu INVENTAR_AGENT_*;
+//  4. opțiunile din linia de comandă.
+//
+// Tokenul poate fi citit dintr-un fișier prin server.token_fisier. Fișierul are
+// prioritatea sursei care l-a indicat: un token dat direct de o sursă cu
+// prioritate mai mare îl înlocuiește. Tokenul și fișierul nu pot fi date de
+// aceeași sursă
+type agentConfig struct {
+	Server struct {
+		URL         string        `yaml:"url"`
+		Token       string        `yaml:"token"`
+		TokenFisier string        `yaml:"token_fisier"`
+		Timeout     time.Duration `yaml:"timeout"`
+		Incercari   int           `yaml:"incercari"`
+	} `yaml:"server"`
+	Intervale struct {
+		Live     time.Duration `yaml:"live"`
+		Inventar time.Duration `yaml:"inventar"`
+	} `yaml:"intervale"`
+	Fisiere struct {
+		Statie   string `yaml:"statie"`
+		Secventa string `yaml:"secventa"`
+		Date     string `yaml:"date"`
+	} `yaml:"fisiere"`
+	Coada struct {
+		Director     string        `yaml:"director"`
+		MaxMB        int64         `yaml:"max_mb"`
+		VarstaMaxima time.Duration `yaml:"varsta_maxima"`
+	} `yaml:"coada"`
+	Colectare struct {
+		SysRoot string `yaml:"sysroot"`
+		OS      string `yaml:"os"`
+	} `yaml:"colectare"`
+}
+
+// Funcție care întoarce configurația implicită
+func defaultAgentConfig() *agentConfig {
+	cfg := &agentConfig{}
+	cfg.Server.URL = "http://localhost:8080/data"
+	cfg.Server.Timeout = 10 * time.Second
+	cfg.Server.Incercari = 4
+	cfg.Intervale.Live = colector.LiveInterval
+	cfg.Intervale.Inventar = colector.InventoryInterval
+	cfg.Fisiere.Statie = "statie.json"
+	cfg.Fisiere.Secventa = "secventa.txt"
+	cfg.Fisiere.Date = "."
+	cfg.Coada.Director = "coada"
+	cfg.Coada.MaxMB = 50
+	// Sub limita implicită a serverului pentru eșantioanele întârziate (aproape 7 zile)
+	cfg.Coada.VarstaMaxima = 6 * 24 * time.Hour
+	cfg.Colectare.SysRoot = colector.SysRoot
+	cfg.Colectare.OS = colector.TargetOS
+	return cfg
+}
+
+// O opțiune de configurare, care poate fi dată prin variabilă de mediu sau în linia de comandă
+type configOption struct {
+	flag  string
+	env   string
+	usage string
+	set   func(value string) error
+}
+
+// Funcție care întoarce setterul unei opțiuni de tip text
+func stringValue(dest *string) func(string) error {
+	return func(value string) error {
+		*dest = value
+		return nil
+	}
+}
+
+// Funcție care întoarce setterul unei opțiuni numerice
+func intValue[T int | int64](dest *T) func(string) error {
+	return func(value string) error {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("valoarea '%s' nu este un număr întreg", value)
+		}
+		*dest = T(n)
+		return nil
+	}
+}
+
+// Funcție care întoarce setterul unei opțiuni de tip durată (de exemplu 10s, 1h)
+func durationValue(dest *time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("valoarea '%s' nu este o durată", value)
+		}
+		*dest = d
+		return nil
+	}
+}
+
+// Opțiunile agentului; numele opțiunilor existente din linia de comandă sunt păstrate
+func agentOptions(cfg *agentConfig) []configOption {
+	return []configOption{
+		{flag: "server", env: "INVENTAR_AGENT_SERVER", usage: "adresa la care se trimit datele", set: stringValue(&cfg.Server.URL)},
+		{flag: "token", env: "INVENTAR_AGENT_TOKEN", usage: "tokenul trimis serverului în antetul Authorization", set: stringValue(&cfg.Server.Token)},
+		{flag: "token-fisier", env: "INVENTAR_AGENT_TOKEN_FISIER", usage: "fișierul din care se citește tokenul", set: stringValue(&cfg.Server.TokenFisier)},
+		{flag: "timeout", env: "INVENTAR_AGENT_TIMEOUT", usage: "timpul maxim de așteptare pentru o cerere către server", set: durationValue(&cfg.Server.Timeout)},
+		{flag: "retries", env: "INVENTAR_AGENT_INCERCARI", usage: "numărul maxim de încercări pentru trimiterea datelor", set: intValue(&cfg.Server.Incercari)},
+		{flag: "interval-live", env: "INVENTAR_AGENT_INTERVAL_LIVE", usage: "intervalul dintre două colectări ale datelor live", set: durationValue(&cfg.Intervale.Live)},
+		{flag: "interval-inventar", env: "INVENTAR_AGENT_INTERVAL_INVENTAR", usage: "intervalul dintre două colectări ale inventarului", set: durationValue(&cfg.Intervale.Inventar)},
+		{flag: "fisier-statie", env: "INVENTAR_AGENT_FISIER_STATIE", usage: "fișierul în care se păstrează identitatea stației", set: stringValue(&cfg.Fisiere.Statie)},
+		{flag: "fisier-secventa", env: "INVENTAR_AGENT_FISIER_SECVENTA", usage: "fișierul în care se păstrează numărul de secvență", set: stringValue(&cfg.Fisiere.Secventa)},
+		{flag: "director-date", env: "INVENTAR_AGENT_DIRECTOR_DATE", usage: "directorul în care se salvează ultimele date trimise (<tip>_data.json)", set: stringValue(&cfg.Fisiere.Date)},
+		{flag: "spool", env: "INVENTAR_AGENT_COADA", usage: "directorul cozii locale pentru datele care nu au putut fi trimise", set: stringValue(&cfg.Coada.Director)},
+		{flag: "spool-max-mb", env: "INVENTAR_AGENT_COADA_MAX_MB", usage: "dimensiunea maximă a cozii locale, în MB", set: intValue(&cfg.Coada.MaxMB)},
+		{flag: "spool-max-age", env: "INVENTAR_AGENT_COADA_VARSTA_MAXIMA", usage: "vârsta maximă a datelor păstrate în coada locală", set: durationValue(&cfg.Coada.VarstaMaxima)},
+		{flag: "sysroot", env: "INVENTAR_AGENT_SYSROOT", usage: "rădăcina sistemului de fișiere din care se citesc informațiile pe Linux", set: stringValue(&cfg.Colectare.SysRoot)},
+		{flag: "os", env: "INVENTAR_AGENT_OS", usage: "sistemul de operare pentru care se interpretează rezultatele (windows, linux, darwin)", set: stringValue(&cfg.Colectare.OS)},
+	}
+}
+
+// Funcție care încarcă configurația agentului din fișier, mediu și linia de comandă
+// Opțiunile care nu țin de configurație (de exemplu -record) sunt înregistrate
+// de apelant în flags înainte de apel
+func loadAgentConfig(flags *flag.FlagSet, args []string) (*agentConfig, error) {
+	cfg := defaultAgentConfig()
+	options := agentOptions(cfg)
+
+	// Opțiunile din linia de comandă sunt reținute și aplicate la final
+	configFile := flags.String("config", os.Getenv("INVENTAR_AGENT_CONFIG"), "fișierul de configurare YAML (INVENTAR_AGENT_CONFIG)")
+	var fromFlags []func() error
+	for _, option := range options {
+		option := option
+		flags.Func(option.flag, fmt.Sprintf("%s (%s)", option.usage, option.env), func(value string) error {
+			fromFlags = append(fromFlags, func() error {
+				err := option.set(value)
+				if err != nil {
+					return fmt.Errorf("opțiunea -%s: %w", option.flag, err)
+				}
+				return nil
+			})
+			return nil
+		})
+	}
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Utilizare: %s [opțiuni]\n", flags.Name())
+		fmt.Fprintf(flags.Output(), "Prioritate: linia de comandă > variabile de mediu > fișierul de configurare > valori implicite\n")
+		flags.PrintDefaults()
+	}
+	err := flags.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		err = cfg.applyLayer(func() error {
+			return readYAMLConfig(*configFile, cfg)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = cfg.applyLayer(func() error {
+		for _, option := range options {
+			if value, ok := os.LookupEnv(option.env); ok {
+				err := option.set(value)
+				if err != nil {
+					return fmt.Errorf("variabila de mediu %s: %w", option.env, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.applyLayer(func() error {
+		for _, apply := range fromFlags {
+			err := apply()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Funcție care aplică o sursă a configurației (fișierul YAML, mediul sau linia
+// de comandă) și citește tokenul din fișierul indicat de această sursă
+// Astfel, tokenul citit dintr-un fișier are prioritatea sursei care a dat fișierul
+func (cfg *agentConfig) applyLayer(apply func() error) error {
+	token, tokenFile := cfg.Server.Token, cfg.Server.TokenFisier
+	cfg.Server.TokenFisier = ""
+
+	err := apply()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Server.TokenFisier == "" {
+		cfg.Server.TokenFisier = tokenFile
+		return nil
+	}
+	if cfg.Server.Token != token {
+		return fmt.Errorf("server.token și server.token_fisier nu pot fi date împreună")
+	}
+	cfg.Server.Token, err = readSecretFile(cfg.Server.TokenFisier)
+	return err
+}
+
+// Funcție care citește fișierul de configurare YAML; cheile necunoscute sunt erori
+func readYAMLConfig(path string, dest interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("eroare la deschiderea fișierului de configurare: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	err = decoder.Decode(dest)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("eroare la citirea fișierului de configurare %s: %w", path, err)
+	}
+	return nil
+}
+
+// Funcție care citește un secret dintr-un fișier, fără spațiile de la capete
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("eroare la citirea secretului din %s: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Funcție care verifică configurația și întoarce toate valorile invalide
+func (cfg *agentConfig) validate() error {
+	var problems []string
+
+	u, err := url.Parse(cfg.Server.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, "server.url: trebuie să fie un URL http:// sau https://")
+	}
+	if cfg.Server.Timeout <= 0 {
+		problems = append(problems, "server.timeout: trebuie să fie pozitiv")
+	}
+	if cfg.Server.Incercari < 1 {
+		problems = append(problems, "server.incercari: trebuie să fie cel puțin 1")
+	}
+	if cfg.Intervale.Live <= 0 {
+		problems = append(problems, "intervale.live: trebuie să fie pozitiv")
+	}
+	if cfg.Intervale.Inventar <= 0 {
+		problems = append(problems, "intervale.inventar: trebuie să fie pozitiv")
+	}
+	if cfg.Fisiere.Statie == "" {
+		problems = append(problems, "fisiere.statie: lipsește")
+	}
+	if cfg.Fisiere.Secventa == "" {
+		problems = append(problems, "fisiere.secventa: lipsește")
+	}
+	if cfg.Coada.Director == "" {
+		problems = append(problems, "coada.director: lipsește")
+	}
+	if cfg.Coada.MaxMB <= 0 {
+		problems = append(problems, "coada.max_mb: trebuie să fie pozitiv")
+	}
+	if cfg.Coada.VarstaMaxima <= 0 {
+		problems = append(problems, "coada.varsta_maxima: trebuie să fie pozitivă")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("configurația nu este validă:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// Funcție care întoarce colectoarele standard, rulate la intervalele din configurație
+func (cfg *agentConfig) collectors() []colector.Collector {
+	var collectors []colector.Collector
+	for _, collector := range colector.NewStandardRegistry().Collectors() {
+		interval := cfg.Intervale.Inventar
+		if colector.PayloadType(collector) == colector.TipLive {
+			interval = cfg.Intervale.Live
+		}
+		collectors = append(collectors, colector.WithInterval(collector, interval))
+	}
+	return collectors
+}
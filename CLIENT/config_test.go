@@ -0,0 +1,190 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Funcție care scrie un fișier în directorul temporar al testului
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Funcție care încarcă configurația agentului cu argumentele date
+func loadTestConfig(args ...string) (*agentConfig, error) {
+	flags := flag.NewFlagSet("agent", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	return loadAgentConfig(flags, args)
+}
+
+// Ultima sursă câștigă: fișier, apoi mediu, apoi linia de comandă; tokenul citit
+// dintr-un fișier are prioritatea sursei care a indicat fișierul
+func TestLoadAgentConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	yamlToken := writeTestFile(t, dir, "token-yaml", "din-yaml\n")
+	envToken := writeTestFile(t, dir, "token-env", " din-env ")
+	config := writeTestFile(t, dir, "agent.yaml", `server:
+  url: http://yaml:8080/data
+  token_fisier: `+yamlToken+`
+  incercari: 2
+intervale:
+  live: 30s
+`)
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		args          []string
+		wantURL       string
+		wantToken     string
+		wantIncercari int
+		wantLive      time.Duration
+	}{
+		{
+			name:          "fișier",
+			wantURL:       "http://yaml:8080/data",
+			wantToken:     "din-yaml",
+			wantIncercari: 2,
+			wantLive:      30 * time.Second,
+		},
+		{
+			name:          "mediu peste fișier",
+			env:           map[string]string{"INVENTAR_AGENT_SERVER": "http://env:8080/data", "INVENTAR_AGENT_TOKEN": "token-env"},
+			wantURL:       "http://env:8080/data",
+			wantToken:     "token-env",
+			wantIncercari: 2,
+			wantLive:      30 * time.Second,
+		},
+		{
+			name:          "fișierul tokenului din mediu",
+			env:           map[string]string{"INVENTAR_AGENT_TOKEN_FISIER": envToken},
+			wantURL:       "http://yaml:8080/data",
+			wantToken:     "din-env",
+			wantIncercari: 2,
+			wantLive:      30 * time.Second,
+		},
+		{
+			name:          "linia de comandă peste mediu",
+			env:           map[string]string{"INVENTAR_AGENT_TOKEN_FISIER": envToken, "INVENTAR_AGENT_INCERCARI": "3"},
+			args:          []string{"-token", "din-flag", "-retries", "5", "-server", "https://flag/data"},
+			wantURL:       "https://flag/data",
+			wantToken:     "din-flag",
+			wantIncercari: 5,
+			wantLive:      30 * time.Second,
+		},
+		{
+			name:          "fișierul tokenului din linia de comandă",
+			env:           map[string]string{"INVENTAR_AGENT_TOKEN": "token-env"},
+			args:          []string{"-token-fisier", envToken, "-interval-live", "1m"},
+			wantURL:       "http://yaml:8080/data",
+			wantToken:     "din-env",
+			wantIncercari: 2,
+			wantLive:      time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INVENTAR_AGENT_CONFIG", config)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := loadTestConfig(tt.args...)
+			if err != nil {
+				t.Fatalf("loadAgentConfig: %v", err)
+			}
+			if cfg.Server.URL != tt.wantURL {
+				t.Errorf("server.url = %q, așteptat %q", cfg.Server.URL, tt.wantURL)
+			}
+			if cfg.Server.Token != tt.wantToken {
+				t.Errorf("server.token = %q, așteptat %q", cfg.Server.Token, tt.wantToken)
+			}
+			if cfg.Server.Incercari != tt.wantIncercari {
+				t.Errorf("server.incercari = %d, așteptat %d", cfg.Server.Incercari, tt.wantIncercari)
+			}
+			if cfg.Intervale.Live != tt.wantLive {
+				t.Errorf("intervale.live = %s, așteptat %s", cfg.Intervale.Live, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestLoadAgentConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	token := writeTestFile(t, dir, "token", "secret")
+
+	tests := []struct {
+		name    string
+		yaml    string
+		env     map[string]string
+		args    []string
+		wantErr []string
+	}{
+		{
+			name:    "valori invalide",
+			args:    []string{"-server", "ftp://server/data", "-retries", "0", "-spool-max-mb", "-1"},
+			wantErr: []string{"server.url", "server.incercari", "coada.max_mb"},
+		},
+		{
+			name:    "cheie necunoscută",
+			yaml:    "server:\n  adresa: http://server/data\n",
+			wantErr: []string{"adresa"},
+		},
+		{
+			name:    "durată invalidă în mediu",
+			env:     map[string]string{"INVENTAR_AGENT_TIMEOUT": "zece"},
+			wantErr: []string{"INVENTAR_AGENT_TIMEOUT"},
+		},
+		{
+			name:    "număr invalid în linia de comandă",
+			args:    []string{"-retries", "multe"},
+			wantErr: []string{"-retries"},
+		},
+		{
+			name:    "token și fișier în aceeași sursă",
+			args:    []string{"-token", "direct", "-token-fisier", token},
+			wantErr: []string{"nu pot fi date împreună"},
+		},
+		{
+			name:    "token și fișier în YAML",
+			yaml:    "server:\n  token: direct\n  token_fisier: " + token + "\n",
+			wantErr: []string{"nu pot fi date împreună"},
+		},
+		{
+			name:    "fișierul tokenului lipsește",
+			env:     map[string]string{"INVENTAR_AGENT_TOKEN_FISIER": filepath.Join(dir, "lipsa")},
+			wantErr: []string{"eroare la citirea secretului"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := ""
+			if tt.yaml != "" {
+				config = writeTestFile(t, t.TempDir(), "agent.yaml", tt.yaml)
+			}
+			t.Setenv("INVENTAR_AGENT_CONFIG", config)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, err := loadTestConfig(tt.args...)
+			if err == nil {
+				t.Fatal("loadAgentConfig: eroare așteptată")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("eroarea %q nu conține %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Numărul de secvență al datelor trimise, păstrat între rulări într-un fișier,
+// astfel încât serverul să poată detecta eșantioanele lipsă sau primite de două ori
+type sequence struct {
+	path string
+	last int64
+}
+
+// Funcție pentru a încărca numărul de secvență din fișierul dat
+// Dacă fișierul nu există, numerotarea începe de la 1
+func loadSequence(path string) (*sequence, error) {
+	seq := &sequence{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return seq, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea fișierului de secvență: %w", err)
+	}
+	seq.last, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la parsarea fișierului de secvență: %w", err)
+	}
+	return seq, nil
+}
+
+// Funcție care întoarce următorul număr de secvență și îl salvează
+func (s *sequence) next() (int64, error) {
+	s.last++
+	err := os.WriteFile(s.path, []byte(strconv.FormatInt(s.last, 10)), 0644)
+	if err != nil {
+		return 0, fmt.Errorf("eroare la scrierea fișierului de secvență: %w", err)
+	}
+	return s.last, nil
+}
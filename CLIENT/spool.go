@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Coada locală pentru datele care nu au putut fi trimise la server
+// Fiecare eșantion este un fișier <momentul colectării în ns>.json, astfel încât
+// ordinea alfabetică a fișierelor este ordinea în care au fost colectate
+type spool struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+}
+
+// Un eșantion din coadă
+type spoolEntry struct {
+	path        string
+	collectedAt time.Time
+	size        int64
+}
+
+// Funcție pentru a deschide coada din directorul dat, creându-l dacă lipsește
+func newSpool(dir string, maxBytes int64, maxAge time.Duration) (*spool, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la crearea directorului cozii: %w", err)
+	}
+	return &spool{dir: dir, maxBytes: maxBytes, maxAge: maxAge}, nil
+}
+
+// Funcție care adaugă un eșantion în coadă și elimină cele mai vechi eșantioane
+// dacă se depășește dimensiunea maximă
+func (s *spool) push(collectedAt time.Time, data []byte) error {
+	name := fmt.Sprintf("%020d.json", collectedAt.UnixNano())
+	tmp := filepath.Join(s.dir, name+".tmp")
+	err := os.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return fmt.Errorf("eroare la scrierea în coadă: %w", err)
+	}
+	err = os.Rename(tmp, filepath.Join(s.dir, name))
+	if err != nil {
+		return fmt.Errorf("eroare la scrierea în coadă: %w", err)
+	}
+
+	entries, err := s.entries()
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+	for len(entries) > 0 && total > s.maxBytes {
+		fmt.Printf("Coada locală este plină, se renunță la eșantionul din %s\n", entries[0].collectedAt.Format(time.RFC3339))
+		err = os.Remove(entries[0].path)
+		if err != nil {
+			return fmt.Errorf("eroare la eliminarea din coadă: %w", err)
+		}
+		total -= entries[0].size
+		entries = entries[1:]
+	}
+	return nil
+}
+
+// Funcție care întoarce eșantioanele din coadă, în ordinea colectării
+// Eșantioanele mai vechi decât vârsta maximă sunt eliminate
+func (s *spool) entries() ([]spoolEntry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea cozii: %w", err)
+	}
+
+	var entries []spoolEntry
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(name, ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		entry := spoolEntry{
+			path:        filepath.Join(s.dir, name),
+			collectedAt: time.Unix(0, nanos),
+			size:        info.Size(),
+		}
+		if time.Since(entry.collectedAt) > s.maxAge {
+			fmt.Printf("Eșantionul din %s este prea vechi și este eliminat din coadă\n", entry.collectedAt.Format(time.RFC3339))
+			err = os.Remove(entry.path)
+			if err != nil {
+				return nil, fmt.Errorf("eroare la eliminarea din coadă: %w", err)
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
+	return entries, nil
+}
+
+// Funcție care întoarce numărul de eșantioane și dimensiunea cozii
+func (s *spool) depth() (int, int64, error) {
+	entries, err := s.entries()
+	if err != nil {
+		return 0, 0, err
+	}
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+	return len(entries), total, nil
+}
+
+// Funcție care trimite eșantioanele din coadă, în ordine, și le elimină pe cele trimise
+// Se oprește la prima eroare, astfel încât ordinea să fie păstrată; eșantioanele
+// respinse definitiv de server sunt eliminate
+func (s *spool) flush(send func(data []byte) error) (int, error) {
+	entries, err := s.entries()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, entry := range entries {
+		data, err := os.ReadFile(entry.path)
+		if err != nil {
+			return sent, fmt.Errorf("eroare la citirea din coadă: %w", err)
+		}
+		err = send(data)
+		if isRejected(err) {
+			fmt.Printf("Eșantionul din %s a fost respins de server: %v\n", entry.collectedAt.Format(time.RFC3339), err)
+		} else if err != nil {
+			return sent, err
+		}
+		err = os.Remove(entry.path)
+		if err != nil {
+			return sent, fmt.Errorf("eroare la eliminarea din coadă: %w", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Funcție care citește conținutul eșantioanelor din coadă, în ordine
+func spoolContents(t *testing.T, s *spool) []string {
+	t.Helper()
+	entries, err := s.entries()
+	if err != nil {
+		t.Fatalf("entries: %v", err)
+	}
+	var contents []string
+	for _, entry := range entries {
+		data, err := os.ReadFile(entry.path)
+		if err != nil {
+			t.Fatalf("citire %s: %v", entry.path, err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Când coada depășește dimensiunea maximă, se renunță la cele mai vechi eșantioane
+func TestSpoolPushEvictsOldest(t *testing.T) {
+	s, err := newSpool(t.TempDir(), 25, time.Hour)
+	if err != nil {
+		t.Fatalf("newSpool: %v", err)
+	}
+	start := time.Now().Add(-time.Minute)
+	for i, data := range []string{"aaaaaaaaaa", "bbbbbbbbbb", "cccccccccc"} {
+		err = s.push(start.Add(time.Duration(i)*time.Second), []byte(data))
+		if err != nil {
+			t.Fatalf("push: %v", err)
+		}
+	}
+
+	got := spoolContents(t, s)
+	want := []string{"bbbbbbbbbb", "cccccccccc"}
+	if !equalStrings(got, want) {
+		t.Errorf("coada = %v, așteptat %v", got, want)
+	}
+	count, size, err := s.depth()
+	if err != nil {
+		t.Fatalf("depth: %v", err)
+	}
+	if count != 2 || size != 20 {
+		t.Errorf("depth = %d, %d; așteptat 2, 20", count, size)
+	}
+}
+
+// Eșantioanele mai vechi decât vârsta maximă sunt eliminate la citirea cozii,
+// iar fișierele care nu sunt eșantioane sunt ignorate
+func TestSpoolEntriesPrunesOld(t *testing.T) {
+	dir := t.TempDir()
+	s, err := newSpool(dir, 1<<20, time.Hour)
+	if err != nil {
+		t.Fatalf("newSpool: %v", err)
+	}
+	now := time.Now()
+	if err = s.push(now.Add(-2*time.Hour), []byte("vechi")); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	if err = s.push(now.Add(-time.Minute), []byte("nou")); err != nil {
+		t.Fatalf("push: %v", err)
+	}
+	// Un fișier temporar rămas de la o scriere întreruptă
+	if err = os.WriteFile(filepath.Join(dir, "00000000000000000001.json.tmp"), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := spoolContents(t, s)
+	if !equalStrings(got, []string{"nou"}) {
+		t.Errorf("coada = %v, așteptat [nou]", got)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(matches) != 1 {
+		t.Errorf("în director au rămas %d eșantioane, așteptat 1", len(matches))
+	}
+}
+
+// Eșantioanele sunt trimise în ordinea colectării, chiar dacă au fost adăugate
+// în altă ordine; cele respinse definitiv sunt eliminate, iar la prima eroare
+// trimiterea se oprește și restul eșantioanelor rămân în coadă
+func TestSpoolFlush(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name      string
+		errors    map[string]error
+		wantSent  []string
+		wantCount int
+		wantLeft  []string
+		wantErr   bool
+	}{
+		{
+			name:      "toate trimise",
+			wantSent:  []string{"1", "2", "3"},
+			wantCount: 3,
+		},
+		{
+			name:      "respins",
+			errors:    map[string]error{"2": &rejectedError{status: "400 Bad Request"}},
+			wantSent:  []string{"1", "2", "3"},
+			wantCount: 3,
+		},
+		{
+			name:      "server indisponibil",
+			errors:    map[string]error{"2": errors.New("conexiune refuzată")},
+			wantSent:  []string{"1", "2"},
+			wantCount: 1,
+			wantLeft:  []string{"2", "3"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := newSpool(t.TempDir(), 1<<20, time.Hour)
+			if err != nil {
+				t.Fatalf("newSpool: %v", err)
+			}
+			for _, i := range []int{3, 1, 2} {
+				err = s.push(start.Add(time.Duration(i)*time.Second), []byte{byte('0' + i)})
+				if err != nil {
+					t.Fatalf("push: %v", err)
+				}
+			}
+
+			var sent []string
+			count, err := s.flush(func(data []byte) error {
+				sent = append(sent, string(data))
+				return tt.errors[string(data)]
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("flush: eroare %v, așteptat eroare: %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount {
+				t.Errorf("flush: %d trimise, așteptat %d", count, tt.wantCount)
+			}
+			if !equalStrings(sent, tt.wantSent) {
+				t.Errorf("ordinea trimiterii = %v, așteptat %v", sent, tt.wantSent)
+			}
+			if left := spoolContents(t, s); !equalStrings(left, tt.wantLeft) {
+				t.Errorf("în coadă au rămas %v, așteptat %v", left, tt.wantLeft)
+			}
+		})
+	}
+}
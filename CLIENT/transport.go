@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Stratul de transport către server: un singur client HTTP, refolosit între
+// iterații, cu timeout pe cerere și reîncercări cu întârziere exponențială
+type transport struct {
+	client      *http.Client
+	serverURL   string
+	token       string // trimis în antetul Authorization, dacă nu este gol
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// Eroare pentru datele respinse de server (4xx, în afară de 429)
+// Datele respinse nu sunt retrimise
+type rejectedError struct {
+	status string
+	body   string
+}
+
+func (e *rejectedError) Error() string {
+	return fmt.Sprintf("serverul a respins datele: %s - %s", e.status, e.body)
+}
+
+// Funcție care arată dacă datele au fost respinse definitiv de server
+func isRejected(err error) bool {
+	var rejected *rejectedError
+	return errors.As(err, &rejected)
+}
+
+// Funcție pentru a crea stratul de transport
+func newTransport(serverURL, token string, timeout time.Duration, maxAttempts int) *transport {
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.MaxIdleConnsPerHost = 2
+	httpTransport.IdleConnTimeout = 90 * time.Second
+
+	return &transport{
+		client:      &http.Client{Transport: httpTransport, Timeout: timeout},
+		serverURL:   serverURL,
+		token:       token,
+		maxAttempts: maxAttempts,
+		baseDelay:   time.Second,
+		maxDelay:    time.Minute,
+	}
+}
+
+// Funcție pentru a trimite datele JSON la server; întoarce răspunsul serverului
+// Erorile de rețea și răspunsurile 5xx sau 429 sunt reîncercate
+func (t *transport) sendJSONToServer(data []byte) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt < t.maxAttempts; attempt++ {
+		body, retryAfter, err := t.post(data)
+		if err == nil {
+			return body, nil
+		}
+		if isRejected(err) {
+			return nil, err
+		}
+		lastErr = err
+		if attempt == t.maxAttempts-1 {
+			break
+		}
+
+		delay := t.backoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		fmt.Printf("Trimiterea a eșuat (încercarea %d din %d): %v; se reîncearcă în %s\n", attempt+1, t.maxAttempts, err, delay.Round(time.Millisecond))
+		time.Sleep(delay)
+	}
+	return nil, lastErr
+}
+
+// Funcție care trimite datele la server o singură dată, fără reîncercări
+// Folosită pentru coada locală: cât timp serverul nu răspunde, colectarea nu
+// așteaptă întârzierile dintre reîncercări
+func (t *transport) sendOnce(data []byte) ([]byte, error) {
+	body, _, err := t.post(data)
+	return body, err
+}
+
+// Funcție care face o singură încercare de trimitere
+// Întoarce și întârzierea cerută de server prin Retry-After, dacă există
+func (t *transport) post(data []byte) ([]byte, time.Duration, error) {
+	req, err := http.NewRequest("POST", t.serverURL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, 0, &rejectedError{status: "cerere invalidă", body: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	// Momentul trimiterii, după care serverul calculează decalajul ceasului
+	req.Header.Set("X-Trimis-La", time.Now().UTC().Format(time.RFC3339Nano))
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("eroare la trimiterea cererii: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Corpul este citit complet, astfel încât conexiunea să poată fi refolosită
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("eroare la citirea răspunsului: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		// Afișează răspunsul serverului
+		fmt.Println("Răspuns de la server:")
+		fmt.Println(resp.Status)
+		fmt.Println(string(body))
+		return body, 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, t.retryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("serverul a returnat o eroare: %s - %s", resp.Status, string(body))
+	}
+	return nil, 0, &rejectedError{status: resp.Status, body: string(body)}
+}
+
+// Funcție care calculează întârzierea înaintea reîncercării: exponențială,
+// cu jitter complet, astfel încât agenții să nu revină toți în același moment
+func (t *transport) backoff(attempt int) time.Duration {
+	delay := t.baseDelay << attempt
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// Funcție care interpretează antetul Retry-After (secunde sau dată HTTP)
+// Întârzierea este limitată la întârzierea maximă a transportului
+func (t *transport) retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = time.Until(at)
+	}
+	if delay < 0 {
+		return 0
+	}
+	if delay > t.maxDelay {
+		return t.maxDelay
+	}
+	return delay
+}
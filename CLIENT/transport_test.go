@@ -0,0 +1,150 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Server de test care răspunde, pe rând, cu codurile date; după ultimul cod
+// răspunde cu 200 OK
+type scriptedServer struct {
+	mu         sync.Mutex
+	statuses   []int
+	retryAfter string
+	attempts   int
+	sentAt     []string
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sentAt = append(s.sentAt, r.Header.Get("X-Trimis-La"))
+	status := http.StatusOK
+	if s.attempts < len(s.statuses) {
+		status = s.statuses[s.attempts]
+	}
+	s.attempts++
+	if status != http.StatusOK && s.retryAfter != "" {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.WriteHeader(status)
+	w.Write([]byte("ok"))
+}
+
+// Funcție care creează un transport cu întârzieri mici, pentru teste
+func testTransport(url string, maxDelay time.Duration) *transport {
+	t := newTransport(url, "secret", 5*time.Second, 4)
+	t.baseDelay = time.Millisecond
+	t.maxDelay = maxDelay
+	return t
+}
+
+func TestTransportRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantAttempts int
+		wantErr      bool
+		wantRejected bool
+	}{
+		{name: "succes", wantAttempts: 1},
+		{name: "5xx reîncercat", statuses: []int{500, 502, 503}, wantAttempts: 4},
+		{name: "429 reîncercat", statuses: []int{429}, wantAttempts: 2},
+		{name: "400 respins", statuses: []int{400}, wantAttempts: 1, wantErr: true, wantRejected: true},
+		{name: "401 respins", statuses: []int{401}, wantAttempts: 1, wantErr: true, wantRejected: true},
+		{name: "413 respins", statuses: []int{413}, wantAttempts: 1, wantErr: true, wantRejected: true},
+		{name: "limita de încercări", statuses: []int{503, 503, 503, 503, 503}, wantAttempts: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := &scriptedServer{statuses: tt.statuses}
+			server := httptest.NewServer(script)
+			defer server.Close()
+
+			_, err := testTransport(server.URL, 10*time.Millisecond).sendJSONToServer([]byte("{}"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("eroare %v, așteptat eroare: %v", err, tt.wantErr)
+			}
+			if isRejected(err) != tt.wantRejected {
+				t.Errorf("isRejected(%v) = %v, așteptat %v", err, isRejected(err), tt.wantRejected)
+			}
+			if script.attempts != tt.wantAttempts {
+				t.Errorf("%d încercări, așteptat %d", script.attempts, tt.wantAttempts)
+			}
+
+			// Fiecare încercare are propriul moment al trimiterii
+			for i, value := range script.sentAt {
+				if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+					t.Errorf("încercarea %d: X-Trimis-La = %q: %v", i+1, value, err)
+				}
+			}
+		})
+	}
+}
+
+// Întârzierea cerută prin Retry-After este respectată, dar nu depășește
+// întârzierea maximă a transportului
+func TestTransportRetryAfter(t *testing.T) {
+	maxDelay := 200 * time.Millisecond
+	tests := []struct {
+		name       string
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{name: "limitat", retryAfter: "3600", min: maxDelay, max: 2 * time.Second},
+		{name: "dată HTTP", retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), min: maxDelay, max: 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := &scriptedServer{statuses: []int{503}, retryAfter: tt.retryAfter}
+			server := httptest.NewServer(script)
+			defer server.Close()
+
+			start := time.Now()
+			_, err := testTransport(server.URL, maxDelay).sendJSONToServer([]byte("{}"))
+			elapsed := time.Since(start)
+			if err != nil {
+				t.Fatalf("sendJSONToServer: %v", err)
+			}
+			if elapsed < tt.min || elapsed > tt.max {
+				t.Errorf("durata %s, așteptat între %s și %s", elapsed, tt.min, tt.max)
+			}
+		})
+	}
+
+	tr := testTransport("", time.Minute)
+	values := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-3", 0},
+		{"3600", time.Minute},
+		{"nu este un număr", 0},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, v := range values {
+		if got := tr.retryAfter(v.value); got != v.want {
+			t.Errorf("retryAfter(%q) = %s, așteptat %s", v.value, got, v.want)
+		}
+	}
+}
+
+// O singură încercare, chiar dacă serverul cere reîncercarea
+func TestTransportSendOnce(t *testing.T) {
+	script := &scriptedServer{statuses: []int{503}}
+	server := httptest.NewServer(script)
+	defer server.Close()
+
+	_, err := testTransport(server.URL, 10*time.Millisecond).sendOnce([]byte("{}"))
+	if err == nil || isRejected(err) {
+		t.Errorf("sendOnce: eroare %v, așteptat o eroare temporară", err)
+	}
+	if script.attempts != 1 {
+		t.Errorf("%d încercări, așteptat 1", script.attempts)
+	}
+}
@@ -0,0 +1,210 @@
+// Pachetul colector contine colectoarele de informatii despre statie
+// (sistem de operare, hardware, software, securitate, utilizator, date live),
+// folosite de agent (CLIENT), de utilitarul care scrie direct in baza de date
+// (TEST) si de extractorul offline (EXTRAGERE)
+package colector
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Numele sectiunilor, folosite si drept chei in datele trimise la server
+const (
+	SectiuneSistemDeOperare = "sistem_de_operare"
+	SectiuneHardware        = "hardware"
+	SectiuneSoftware        = "software"
+	SectiuneSecuritate      = "securitate"
+	SectiuneUtilizator      = "utilizator"
+	SectiuneLive            = "live"
+)
+
+// Datele intoarse de un colector: una dintre structurile pachetului
+// (*OSInfo, *HardwareInfo, *SoftwareInfo, SecurityInfo, *UserInfo, *LiveSystemInfo)
+type Data interface {
+	Section() string
+}
+
+func (*OSInfo) Section() string         { return SectiuneSistemDeOperare }
+func (*HardwareInfo) Section() string   { return SectiuneHardware }
+func (*SoftwareInfo) Section() string   { return SectiuneSoftware }
+func (SecurityInfo) Section() string    { return SectiuneSecuritate }
+func (*UserInfo) Section() string       { return SectiuneUtilizator }
+func (*LiveSystemInfo) Section() string { return SectiuneLive }
+
+// Un colector obtine o sectiune de informatii despre statie
+// Interval este perioada recomandata intre doua colectari
+type Collector interface {
+	Name() string
+	Interval() time.Duration
+	Collect(ctx context.Context) (Data, error)
+}
+
+// Colector construit dintr-o functie
+type collectorFunc struct {
+	name     string
+	interval time.Duration
+	collect  func(ctx context.Context) (Data, error)
+}
+
+func (c collectorFunc) Name() string                              { return c.name }
+func (c collectorFunc) Interval() time.Duration                   { return c.interval }
+func (c collectorFunc) Collect(ctx context.Context) (Data, error) { return c.collect(ctx) }
+
+// Functie pentru a crea un colector dintr-o functie
+func NewCollector(name string, interval time.Duration, collect func(ctx context.Context) (Data, error)) Collector {
+	return collectorFunc{name: name, interval: interval, collect: collect}
+}
+
+// Functie care intoarce colectorul dat, rulat la alt interval
+func WithInterval(collector Collector, interval time.Duration) Collector {
+	return NewCollector(collector.Name(), interval, collector.Collect)
+}
+
+// Perioadele implicite: inventarul se schimba rar, datele live des
+const (
+	InventoryInterval = time.Hour
+	LiveInterval      = 10 * time.Second
+)
+
+// Functie care intoarce colectoarele standard, in ordinea in care sunt rulate
+func StandardCollectors() []Collector {
+	return []Collector{
+		NewCollector(SectiuneSistemDeOperare, InventoryInterval, func(ctx context.Context) (Data, error) {
+			return GetOSInfo(ctx)
+		}),
+		NewCollector(SectiuneHardware, InventoryInterval, func(ctx context.Context) (Data, error) {
+			return GetHardwareInfo(ctx)
+		}),
+		NewCollector(SectiuneSoftware, InventoryInterval, func(ctx context.Context) (Data, error) {
+			programs, err := GetInstalledPrograms(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return &SoftwareInfo{ProgrameInstalate: programs}, nil
+		}),
+		NewCollector(SectiuneSecuritate, InventoryInterval, func(ctx context.Context) (Data, error) {
+			security, err := GetSecurityInfo(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return SecurityInfo(security), nil
+		}),
+		NewCollector(SectiuneUtilizator, InventoryInterval, func(ctx context.Context) (Data, error) {
+			return GetCurrentUserInfo(ctx)
+		}),
+		NewCollector(SectiuneLive, LiveInterval, func(ctx context.Context) (Data, error) {
+			return GetLiveSystemInfo(ctx)
+		}),
+	}
+}
+
+// Registrul colectoarelor folosite de un program
+type Registry struct {
+	collectors []Collector
+	byName     map[string]Collector
+}
+
+// Functie pentru a crea un registru gol
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]Collector)}
+}
+
+// Functie pentru a crea un registru cu colectoarele standard
+func NewStandardRegistry() *Registry {
+	registry := NewRegistry()
+	for _, collector := range StandardCollectors() {
+		registry.collectors = append(registry.collectors, collector)
+		registry.byName[collector.Name()] = collector
+	}
+	return registry
+}
+
+// Functie pentru a adauga un colector in registru
+func (r *Registry) Register(collector Collector) error {
+	if _, ok := r.byName[collector.Name()]; ok {
+		return fmt.Errorf("colectorul '%s' este deja înregistrat", collector.Name())
+	}
+	r.collectors = append(r.collectors, collector)
+	r.byName[collector.Name()] = collector
+	return nil
+}
+
+// Functie care intoarce colectorul cu numele dat
+func (r *Registry) Get(name string) (Collector, bool) {
+	collector, ok := r.byName[name]
+	return collector, ok
+}
+
+// Functie care intoarce colectoarele, in ordinea inregistrarii
+func (r *Registry) Collectors() []Collector {
+	return append([]Collector(nil), r.collectors...)
+}
+
+// Rezultatul rularii unui colector
+type Result struct {
+	Collector   Collector
+	Data        Data
+	Err         error
+	CollectedAt time.Time // momentul colectarii, in UTC
+}
+
+// Functie care ruleaza colectoarele date, in ordine, si intoarce rezultatele
+// Erorile nu opresc colectarea; fiecare rezultat are propria eroare
+func Collect(ctx context.Context, collectors []Collector) []Result {
+	results := make([]Result, 0, len(collectors))
+	for _, collector := range collectors {
+		data, err := collector.Collect(ctx)
+		if err != nil {
+			err = fmt.Errorf("colectorul '%s': %w", collector.Name(), err)
+		}
+		results = append(results, Result{Collector: collector, Data: data, Err: err, CollectedAt: time.Now().UTC()})
+	}
+	return results
+}
+
+// Functie care intoarce valoarea folosita cand colectorul unei sectiuni esueaza
+func emptyData(section string) Data {
+	switch section {
+	case SectiuneSistemDeOperare:
+		return &OSInfo{}
+	case SectiuneHardware:
+		return &HardwareInfo{}
+	case SectiuneSoftware:
+		return &SoftwareInfo{ProgrameInstalate: []ProgramInfo{}}
+	case SectiuneSecuritate:
+		return SecurityInfo("N/A")
+	case SectiuneUtilizator:
+		return &UserInfo{}
+	case SectiuneLive:
+		return &LiveSystemInfo{}
+	}
+	return nil
+}
+
+// Functie care construieste datele in formatul asteptat de server: cate o
+// cheie pentru fiecare sectiune, iar datele live direct la radacina
+// Sectiunile al caror colector a esuat primesc valori goale
+func SystemInfo(results []Result) map[string]interface{} {
+	systemInfo := make(map[string]interface{})
+	for _, result := range results {
+		data := result.Data
+		if result.Err != nil || data == nil {
+			data = emptyData(result.Collector.Name())
+		}
+		if data == nil {
+			continue
+		}
+
+		if liveInfo, ok := data.(*LiveSystemInfo); ok {
+			systemInfo["utilizare_cpu"] = liveInfo.UtilizareCPU
+			systemInfo["utilizare_ram"] = liveInfo.UtilizareRAM
+			systemInfo["trafic_retea_bytes_trimisi"] = liveInfo.TraficTrimis
+			systemInfo["trafic_retea_bytes_primiti"] = liveInfo.TraficReceptionat
+			continue
+		}
+		systemInfo[data.Section()] = data
+	}
+	return systemInfo
+}
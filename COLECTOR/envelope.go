@@ -0,0 +1,59 @@
+package colector
+
+import (
+	"time"
+)
+
+// Versiunea formatului datelor trimise la server
+// Versiunea 1 este formatul vechi, cu sectiunile si datele live direct la radacina
+const SchemaVersion = 2
+
+// Sectiunile trimise la server; lipsesc cele care nu au fost colectate
+type Sections struct {
+	SistemDeOperare *OSInfo         `json:"sistem_de_operare,omitempty"`
+	Hardware        *HardwareInfo   `json:"hardware,omitempty"`
+	Software        *SoftwareInfo   `json:"software,omitempty"`
+	Securitate      *SecurityInfo   `json:"securitate,omitempty"`
+	Utilizator      *UserInfo       `json:"utilizator,omitempty"`
+	Live            *LiveSystemInfo `json:"live,omitempty"`
+}
+
+// Functie care adauga datele unui colector in sectiunea corespunzatoare
+func (s *Sections) Set(data Data) {
+	switch data := data.(type) {
+	case *OSInfo:
+		s.SistemDeOperare = data
+	case *HardwareInfo:
+		s.Hardware = data
+	case *SoftwareInfo:
+		s.Software = data
+	case SecurityInfo:
+		s.Securitate = &data
+	case *UserInfo:
+		s.Utilizator = data
+	case *LiveSystemInfo:
+		s.Live = data
+	}
+}
+
+// Plicul datelor trimise la server
+type Envelope struct {
+	VersiuneSchema int               `json:"versiune_schema"`
+	VersiuneAgent  string            `json:"versiune_agent"`
+	Tip            string            `json:"tip"`
+	Statie         *StationIdentity  `json:"statie"`
+	ColectatLa     time.Time         `json:"colectat_la"`
+	Secventa       int64             `json:"secventa"`
+	Hashuri        map[string]string `json:"hashuri,omitempty"`
+	Sectiuni       Sections          `json:"sectiuni"`
+}
+
+// Functie pentru a crea un plic de tipul dat, cu momentul colectarii dat
+// Identitatea statiei, versiunea agentului si numarul de secventa sunt completate de agent
+func NewEnvelope(tip string, collectedAt time.Time) *Envelope {
+	return &Envelope{
+		VersiuneSchema: SchemaVersion,
+		Tip:            tip,
+		ColectatLa:     collectedAt,
+	}
+}
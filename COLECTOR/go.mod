@@ -0,0 +1,13 @@
+module colector
+
+go 1.22.4
+
+require github.com/shirou/gopsutil v3.21.11+incompatible
+
+require (
+	github.com/go-ole/go-ole v1.2.6 // indirect
+	github.com/tklauser/go-sysconf v0.3.14 // indirect
+	github.com/tklauser/numcpus v0.8.0 // indirect
+	github.com/yusufpapurcu/wmi v1.2.4 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
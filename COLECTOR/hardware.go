@@ -0,0 +1,198 @@
+package colector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Structura pentru informatii despre hardware
+type HardwareInfo struct {
+	Procesor      string `json:"procesor"`
+	Nuclee        int    `json:"nuclee"`
+	FireExecutie  int    `json:"fire_executie"`
+	Frecventa     string `json:"frecventa"`
+	MemorieRAM    string `json:"memorie_ram"`
+	TipStocare    string `json:"tip_stocare"`
+	CapacitateHDD string `json:"capacitate_hdd"`
+	PlacaDeBaza   string `json:"placa_de_baza"`
+	PlacaVideo    string `json:"placa_video"`
+	ModelStocare  string `json:"model_stocare,omitempty"`
+	BIOS          string `json:"bios,omitempty"`
+	// Seria placii de baza, folosita de server pentru a observa inlocuirea placii
+	SerialPlacaDeBaza string `json:"serial_placa_de_baza,omitempty"`
+}
+
+// Valori pe care unii producatori le pun in locul seriei placii de baza
+var invalidBoardSerials = map[string]bool{
+	"":                       true,
+	"n/a":                    true,
+	"none":                   true,
+	"0":                      true,
+	"default string":         true,
+	"to be filled by o.e.m.": true,
+	"system serial number":   true,
+	"not applicable":         true,
+}
+
+// Functie care intoarce seria placii de baza, sau "N/A" daca seria lipseste
+// ori este o valoare generica
+func boardSerial(serial string) string {
+	serial = strings.TrimSpace(serial)
+	if invalidBoardSerials[strings.ToLower(serial)] {
+		return "N/A"
+	}
+	return serial
+}
+
+// Functie pentru a obtine informatii despre hardware
+func GetHardwareInfo(ctx context.Context) (*HardwareInfo, error) {
+	// Pe Linux informatiile sunt citite din /proc si /sys, sub radacina SysRoot
+	if TargetOS == "linux" {
+		return getLinuxHardwareInfo(SysRoot)
+	}
+
+	hardwareInfo := &HardwareInfo{}
+
+	// Obtine informatii despre procesor
+	out, err := Runner.Run(ctx, "cmd", "/c", "wmic cpu get Name,NumberOfCores,NumberOfLogicalProcessors,MaxClockSpeed /FORMAT:LIST")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic cpu get ...': %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "=") {
+			fields := strings.Split(line, "=")
+			switch strings.TrimSpace(fields[0]) {
+			case "Name":
+				hardwareInfo.Procesor = strings.TrimSpace(fields[1])
+			case "NumberOfCores":
+				hardwareInfo.Nuclee, _ = strconv.Atoi(strings.TrimSpace(fields[1]))
+			case "NumberOfLogicalProcessors":
+				hardwareInfo.FireExecutie, _ = strconv.Atoi(strings.TrimSpace(fields[1]))
+			case "MaxClockSpeed":
+				hardwareInfo.Frecventa = strings.TrimSpace(fields[1]) + " MHz"
+			}
+		}
+	}
+
+	// Obtine informatii despre memoria RAM
+	out, err = Runner.Run(ctx, "cmd", "/c", "wmic memorychip get Capacity /FORMAT:LIST")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic memorychip get Capacity': %w", err)
+	}
+	lines = strings.Split(strings.TrimSpace(string(out)), "\n")
+	var totalRAM uint64
+	for _, line := range lines {
+		if strings.Contains(line, "=") {
+			fields := strings.Split(line, "=")
+			if len(fields) > 1 {
+				capacity, _ := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
+				totalRAM += capacity
+			}
+		}
+	}
+	hardwareInfo.MemorieRAM = fmt.Sprintf("%d GB", totalRAM/(1024*1024*1024))
+
+	// Obtine informatii despre stocare (inclusiv SSD-uri NVMe)
+	out, err = Runner.Run(ctx, "powershell", "-Command", "Get-PhysicalDisk | Where-Object {$_.BusType -eq 'NVMe'} | ConvertTo-Json")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii PowerShell: %w", err)
+	}
+
+	//  Verificam dacă rezultatul este un array JSON sau un singur obiect
+	// Daca nu exista niciun disc NVMe, PowerShell nu afiseaza nimic
+	var disks []map[string]interface{}
+	if strings.TrimSpace(string(out)) == "" {
+		// Nu exista discuri
+	} else if strings.HasPrefix(strings.TrimSpace(string(out)), "[") {
+		// Este un array JSON
+		err = json.Unmarshal(out, &disks)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la parsarea JSON (array): %w", err)
+		}
+	} else {
+		// Este un singur obiect JSON
+		var disk map[string]interface{}
+		err = json.Unmarshal(out, &disk)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la parsarea JSON (obiect): %w", err)
+		}
+		disks = append(disks, disk) // Adăugăm obiectul la un slice
+	}
+	if len(disks) > 0 {
+		// Extragem informatiile pentru primul disc gasit
+		disk := disks[0]
+		hardwareInfo.TipStocare = mediaTypeName(disk["MediaType"])
+		hardwareInfo.ModelStocare, _ = disk["FriendlyName"].(string)
+
+		// Convertim dimensiunea discului din bytes in GB
+		sizeBytes, _ := disk["Size"].(float64)
+		hardwareInfo.CapacitateHDD = fmt.Sprintf("%d GB", int64(sizeBytes)/(1024*1024*1024))
+	} else {
+		hardwareInfo.TipStocare = "N/A"
+		hardwareInfo.CapacitateHDD = "N/A"
+	}
+
+	// Obtine informatii despre placa de baza
+	out, err = Runner.Run(ctx, "cmd", "/c", "wmic baseboard get Manufacturer,Product,SerialNumber /FORMAT:LIST")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic baseboard get ...': %w", err)
+	}
+	lines = strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "=") {
+			fields := strings.Split(line, "=")
+			switch strings.TrimSpace(fields[0]) {
+			case "Manufacturer":
+				hardwareInfo.PlacaDeBaza = strings.TrimSpace(fields[1])
+			case "Product":
+				hardwareInfo.PlacaDeBaza += " " + strings.TrimSpace(fields[1])
+			case "SerialNumber":
+				hardwareInfo.SerialPlacaDeBaza = boardSerial(fields[1])
+			}
+		} else if hardwareInfo.PlacaDeBaza != "" {
+			break // Se obtin doar informatiile despre prima placa de baza
+		}
+	}
+
+	// Obtine informatii despre placa video
+	out, err = Runner.Run(ctx, "cmd", "/c", "wmic path win32_videocontroller get Name /FORMAT:LIST")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic path win32_videocontroller get Name': %w", err)
+	}
+	lines = strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "=") {
+			fields := strings.Split(line, "=")
+			if len(fields) > 1 {
+				hardwareInfo.PlacaVideo = strings.TrimSpace(fields[1])
+			}
+		}
+		break // Se obtin doar informatiile despre prima placa video
+	}
+
+	return hardwareInfo, nil
+}
+
+// Functie care traduce campul MediaType din Get-PhysicalDisk
+// ConvertTo-Json serializeaza enumerarea ca numar (3 = HDD, 4 = SSD, 5 = SCM)
+func mediaTypeName(mediaType interface{}) string {
+	switch value := mediaType.(type) {
+	case string:
+		return value
+	case float64:
+		switch value {
+		case 3:
+			return "HDD"
+		case 4:
+			return "SSD"
+		case 5:
+			return "SCM"
+		}
+		return "Unspecified"
+	}
+	return "N/A"
+}
@@ -0,0 +1,213 @@
+package colector
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Structura pentru identitatea statiei, trimisa in fiecare payload si
+// pastrata intr-un fisier local de stare
+type StationIdentity struct {
+	ID          string `json:"id"`
+	MachineID   string `json:"machine_id"`
+	ProductUUID string `json:"product_uuid"`
+	MAC         string `json:"mac"`
+	Hostname    string `json:"hostname"`
+}
+
+// Valori pe care unii producatori le pun in DMI in loc de un UUID real
+var invalidProductUUIDs = map[string]bool{
+	"":                                     true,
+	"00000000-0000-0000-0000-000000000000": true,
+	"ffffffff-ffff-ffff-ffff-ffffffffffff": true,
+	"03000200-0400-0500-0006-000700080009": true,
+}
+
+// Functie pentru a obtine identitatea statiei
+// ID-ul este citit din fisierul de stare; daca fisierul nu exista, ID-ul este
+// derivat din cel mai stabil identificator hardware disponibil (UUID-ul DMI,
+// apoi machine-id, apoi adresa MAC), astfel incat o masina reinstalata sau
+// redenumita sa fie recunoscuta ca aceeasi statie
+// Un fisier de stare copiat pe alta masina (imagine de instalare, masina virtuala
+// clonata) are alti identificatori hardware; atunci ID-ul este derivat din nou
+func LoadStationIdentity(ctx context.Context, stateFile string) (*StationIdentity, error) {
+	current := &StationIdentity{
+		MachineID:   readMachineID(ctx),
+		ProductUUID: readProductUUID(ctx),
+		MAC:         primaryMAC(),
+	}
+	current.Hostname, _ = os.Hostname()
+
+	stored := &StationIdentity{}
+	data, err := os.ReadFile(stateFile)
+	if err == nil {
+		err = json.Unmarshal(data, stored)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la parsarea fisierului de stare '%s': %w", stateFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("eroare la citirea fisierului de stare '%s': %w", stateFile, err)
+	}
+
+	current.ID, err = resolveStationID(current, stored)
+	if err != nil {
+		return nil, err
+	}
+
+	if *current != *stored {
+		data, err = json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("eroare la serializarea identitatii statiei: %w", err)
+		}
+		err = os.WriteFile(stateFile, data, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la scrierea fisierului de stare '%s': %w", stateFile, err)
+		}
+	}
+
+	return current, nil
+}
+
+// Functie care alege ID-ul statiei: cel din fisierul de stare, daca fisierul
+// a fost scris pe aceeasi masina, altfel un ID derivat din identificatorii actuali
+// Sunt comparati doar identificatorii cunoscuti in ambele identitati; schimbarea
+// hostname-ului sau a adresei MAC (placa de retea, dock) pastreaza ID-ul
+func resolveStationID(current, stored *StationIdentity) (string, error) {
+	if stored.ID == "" || !sameMachine(current, stored) {
+		return deriveStationID(current)
+	}
+	return stored.ID, nil
+}
+
+// Functie care arata daca doua identitati descriu aceeasi masina
+func sameMachine(a, b *StationIdentity) bool {
+	if a.ProductUUID != "" && b.ProductUUID != "" && a.ProductUUID != b.ProductUUID {
+		return false
+	}
+	if a.MachineID != "" && b.MachineID != "" && a.MachineID != b.MachineID {
+		return false
+	}
+	return true
+}
+
+// Functie care deriva ID-ul statiei din identificatorii hardware
+// Daca niciunul nu este disponibil, se genereaza un ID aleator
+func deriveStationID(identity *StationIdentity) (string, error) {
+	var source string
+	switch {
+	case identity.ProductUUID != "":
+		source = "product-uuid:" + identity.ProductUUID
+	case identity.MachineID != "":
+		source = "machine-id:" + identity.MachineID
+	case identity.MAC != "":
+		source = "mac:" + identity.MAC
+	}
+
+	var id []byte
+	if source != "" {
+		sum := sha256.Sum256([]byte(source))
+		id = sum[:16]
+	} else {
+		id = make([]byte, 16)
+		_, err := rand.Read(id)
+		if err != nil {
+			return "", fmt.Errorf("eroare la generarea ID-ului statiei: %w", err)
+		}
+	}
+
+	// Formatam ID-ul ca UUID (versiunea 4, varianta RFC 4122)
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	s := hex.EncodeToString(id)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32], nil
+}
+
+// Functie pentru a obtine machine-id-ul sistemului de operare
+func readMachineID(ctx context.Context) string {
+	switch TargetOS {
+	case "windows":
+		out, err := Runner.Run(ctx, "reg", "query", `HKLM\SOFTWARE\Microsoft\Cryptography`, "/v", "MachineGuid")
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 3 && fields[0] == "MachineGuid" {
+				return strings.ToLower(fields[2])
+			}
+		}
+	default:
+		for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+			data, err := os.ReadFile(path)
+			if err == nil && strings.TrimSpace(string(data)) != "" {
+				return strings.TrimSpace(string(data))
+			}
+		}
+	}
+	return ""
+}
+
+// Functie pentru a obtine UUID-ul produsului din DMI/SMBIOS
+func readProductUUID(ctx context.Context) string {
+	var uuid string
+	switch TargetOS {
+	case "windows":
+		out, err := Runner.Run(ctx, "cmd", "/c", "wmic csproduct get UUID /FORMAT:LIST")
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			fields := strings.SplitN(line, "=", 2)
+			if len(fields) == 2 && strings.TrimSpace(fields[0]) == "UUID" {
+				uuid = strings.TrimSpace(fields[1])
+			}
+		}
+	default:
+		// Fisierul poate fi citit doar de root; in lipsa lui folosim machine-id
+		data, err := os.ReadFile("/sys/class/dmi/id/product_uuid")
+		if err != nil {
+			return ""
+		}
+		uuid = strings.TrimSpace(string(data))
+	}
+
+	uuid = strings.ToLower(uuid)
+	if invalidProductUUIDs[uuid] {
+		return ""
+	}
+	return uuid
+}
+
+// Functie pentru a obtine adresa MAC a interfetei principale
+// Se alege prima interfata fizica (dupa nume) care nu este loopback
+func primaryMAC() string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return ""
+	}
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Name < interfaces[j].Name
+	})
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		if runtime.GOOS == "linux" {
+			// Interfetele virtuale (docker, bridge, veth) nu au legatura 'device'
+			if _, err := os.Stat("/sys/class/net/" + iface.Name + "/device"); err != nil {
+				continue
+			}
+		}
+		return iface.HardwareAddr.String()
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package colector
+
+import "testing"
+
+func TestResolveStationID(t *testing.T) {
+	stored := &StationIdentity{
+		ID:          "5d1c0a3e-8f27-4b6a-9c11-2e4f7a9b0c3d",
+		MachineID:   "b7e3c1d2a4f5468e9a0b1c2d3e4f5a6b",
+		ProductUUID: "4c4c4544-0042-3510-8052-b4c04f4b4e32",
+		MAC:         "00:1a:2b:3c:4d:5e",
+		Hostname:    "pc-contabilitate",
+	}
+	clone := &StationIdentity{
+		MachineID:   stored.MachineID,
+		ProductUUID: "564d8c2e-7a1b-4f3e-9d6c-0b5a4e3d2c1f",
+		MAC:         "00:50:56:aa:bb:cc",
+		Hostname:    stored.Hostname,
+	}
+	cloneID, err := deriveStationID(clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		current StationIdentity
+		want    string
+	}{
+		{"aceeasi masina", StationIdentity{MachineID: stored.MachineID, ProductUUID: stored.ProductUUID, MAC: stored.MAC, Hostname: stored.Hostname}, stored.ID},
+		{"redenumita, alta placa de retea", StationIdentity{MachineID: stored.MachineID, ProductUUID: stored.ProductUUID, MAC: "00:1a:2b:99:88:77", Hostname: "pc-financiar"}, stored.ID},
+		// Fara drepturi de root UUID-ul DMI nu poate fi citit; machine-id se potriveste
+		{"UUID DMI indisponibil", StationIdentity{MachineID: stored.MachineID, Hostname: stored.Hostname}, stored.ID},
+		{"masina virtuala clonata", *clone, cloneID},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveStationID(&tc.current, stored)
+			if err != nil {
+				t.Fatalf("resolveStationID: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("resolveStationID = %q, asteptat %q", got, tc.want)
+			}
+		})
+	}
+
+	// O imagine de instalare copiata cu tot cu fisierul de stare, pe alt hardware
+	// si cu machine-id regenerat, primeste un ID nou
+	image := &StationIdentity{MachineID: "0f1e2d3c4b5a69788796a5b4c3d2e1f0", Hostname: stored.Hostname}
+	got, err := resolveStationID(image, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == stored.ID {
+		t.Errorf("resolveStationID a pastrat ID-ul masinii sursa pentru un machine-id diferit")
+	}
+}
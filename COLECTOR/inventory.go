@@ -0,0 +1,109 @@
+package colector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Functie care calculeaza hash-ul unei sectiuni de inventar
+// Programele instalate sunt sortate, astfel incat ordinea in care le intorc
+// managerii de pachete sa nu schimbe hash-ul
+func SectionHash(data Data) (string, error) {
+	if softwareInfo, ok := data.(*SoftwareInfo); ok {
+		programs := append([]ProgramInfo(nil), softwareInfo.ProgrameInstalate...)
+		sort.Slice(programs, func(i, j int) bool {
+			if programs[i].Nume != programs[j].Nume {
+				return programs[i].Nume < programs[j].Nume
+			}
+			return programs[i].Versiune < programs[j].Versiune
+		})
+		data = &SoftwareInfo{ProgrameInstalate: programs}
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("eroare la serializarea sectiunii '%s': %w", data.Section(), err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Starea inventarului trimis la server: ultimele date ale fiecarei sectiuni,
+// hash-urile lor si hash-urile pe care serverul a confirmat ca le cunoaste
+type InventoryState struct {
+	sections     map[string]Data
+	hashes       map[string]string
+	acknowledged map[string]string
+	collectedAt  time.Time
+}
+
+// Functie pentru a crea starea inventarului; serverul nu cunoaste inca nicio sectiune
+func NewInventoryState() *InventoryState {
+	return &InventoryState{
+		sections:     make(map[string]Data),
+		hashes:       make(map[string]string),
+		acknowledged: make(map[string]string),
+	}
+}
+
+// Functie care actualizeaza sectiunile cu rezultatele unei colectari
+// Sectiunile al caror colector a esuat isi pastreaza datele anterioare
+func (s *InventoryState) Update(results []Result) error {
+	for _, result := range results {
+		if result.Err != nil || result.Data == nil || PayloadType(result.Collector) != TipInventar {
+			continue
+		}
+		hash, err := SectionHash(result.Data)
+		if err != nil {
+			return err
+		}
+		s.sections[result.Collector.Name()] = result.Data
+		s.hashes[result.Collector.Name()] = hash
+		if result.CollectedAt.After(s.collectedAt) {
+			s.collectedAt = result.CollectedAt
+		}
+	}
+	return nil
+}
+
+// Functie care construieste plicul de inventar pentru server: hash-urile
+// tuturor sectiunilor si doar sectiunile pe care serverul nu le cunoaste
+func (s *InventoryState) Payload() *Envelope {
+	envelope := NewEnvelope(TipInventar, s.collectedAt)
+	envelope.Hashuri = make(map[string]string, len(s.hashes))
+	for section, hash := range s.hashes {
+		envelope.Hashuri[section] = hash
+		if s.acknowledged[section] != hash {
+			envelope.Sectiuni.Set(s.sections[section])
+		}
+	}
+	return envelope
+}
+
+// Functie care retine hash-urile confirmate de server
+func (s *InventoryState) Acknowledge(known map[string]string) {
+	s.acknowledged = make(map[string]string, len(known))
+	for section, hash := range known {
+		s.acknowledged[section] = hash
+	}
+}
+
+// Functie care arata daca nu a fost colectata inca nicio sectiune; serverul
+// respinge inventarul fara hash-uri, asa ca acesta nu trebuie trimis
+func (s *InventoryState) Empty() bool {
+	return len(s.hashes) == 0
+}
+
+// Functie care arata daca exista sectiuni pe care serverul nu le cunoaste
+func (s *InventoryState) Pending() bool {
+	for section, hash := range s.hashes {
+		if s.acknowledged[section] != hash {
+			return true
+		}
+	}
+	return false
+}
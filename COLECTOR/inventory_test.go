@@ -0,0 +1,41 @@
+package colector
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// Cand toate colectoarele de inventar esueaza, nu exista hash-uri de trimis;
+// dupa prima colectare reusita, inventarul poate fi trimis
+func TestInventoryStateEmpty(t *testing.T) {
+	osCollector := NewCollector(SectiuneSistemDeOperare, InventoryInterval, nil)
+	hardware := NewCollector(SectiuneHardware, InventoryInterval, nil)
+	now := time.Now().UTC()
+
+	inventory := NewInventoryState()
+	err := inventory.Update([]Result{
+		{Collector: osCollector, Err: errors.New("wmic indisponibil"), CollectedAt: now},
+		{Collector: hardware, Err: errors.New("acces refuzat"), CollectedAt: now},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !inventory.Empty() || inventory.Pending() {
+		t.Errorf("dupa colectari esuate: Empty = %v, Pending = %v", inventory.Empty(), inventory.Pending())
+	}
+
+	err = inventory.Update([]Result{
+		{Collector: osCollector, Data: &OSInfo{Nume: "Ubuntu"}, CollectedAt: now},
+		{Collector: hardware, Err: errors.New("acces refuzat"), CollectedAt: now},
+	})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if inventory.Empty() || !inventory.Pending() {
+		t.Errorf("dupa o colectare reusita: Empty = %v, Pending = %v", inventory.Empty(), inventory.Pending())
+	}
+	if hashes := inventory.Payload().Hashuri; len(hashes) != 1 || hashes[SectiuneSistemDeOperare] == "" {
+		t.Errorf("Payload().Hashuri = %v", hashes)
+	}
+}
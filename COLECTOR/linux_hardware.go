@@ -0,0 +1,282 @@
+package colector
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Producatori de placi video, dupa ID-ul PCI, folositi cand pci.ids lipseste
+var pciVendors = map[string]string{
+	"0x8086": "Intel",
+	"0x10de": "NVIDIA",
+	"0x1002": "AMD",
+	"0x1a03": "ASPEED",
+	"0x15ad": "VMware",
+	"0x1af4": "Red Hat (virtio)",
+	"0x1234": "QEMU",
+	"0x80ee": "VirtualBox",
+}
+
+// Functie pentru a obtine informatii despre hardware pe Linux
+// Toate informatiile sunt citite din /proc si /sys, sub radacina data
+func getLinuxHardwareInfo(root string) (*HardwareInfo, error) {
+	hardwareInfo := &HardwareInfo{}
+
+	// Obtine informatii despre procesor
+	err := readLinuxCPUInfo(root, hardwareInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtine informatii despre memoria RAM
+	err = readLinuxMemInfo(root, hardwareInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Obtine informatii despre placa de baza si BIOS
+	dmi := filepath.Join(root, "sys/class/dmi/id")
+	hardwareInfo.PlacaDeBaza = strings.TrimSpace(readTrimmed(filepath.Join(dmi, "board_vendor")) + " " + readTrimmed(filepath.Join(dmi, "board_name")))
+	hardwareInfo.BIOS = strings.TrimSpace(readTrimmed(filepath.Join(dmi, "bios_vendor")) + " " + readTrimmed(filepath.Join(dmi, "bios_version")))
+	if hardwareInfo.PlacaDeBaza == "" {
+		hardwareInfo.PlacaDeBaza = "N/A"
+	}
+	if hardwareInfo.BIOS == "" {
+		hardwareInfo.BIOS = "N/A"
+	}
+	// board_serial poate fi citit doar de root; altfel seria ramane N/A
+	hardwareInfo.SerialPlacaDeBaza = boardSerial(readTrimmed(filepath.Join(dmi, "board_serial")))
+
+	// Obtine informatii despre stocare
+	readLinuxDiskInfo(root, hardwareInfo)
+
+	// Obtine informatii despre placa video
+	hardwareInfo.PlacaVideo = readLinuxGPUInfo(root)
+
+	return hardwareInfo, nil
+}
+
+// Functie pentru a citi /proc/cpuinfo
+func readLinuxCPUInfo(root string, hardwareInfo *HardwareInfo) error {
+	file, err := os.Open(filepath.Join(root, "proc/cpuinfo"))
+	if err != nil {
+		return fmt.Errorf("eroare la citirea /proc/cpuinfo: %w", err)
+	}
+	defer file.Close()
+
+	var physicalID string
+	var mhz float64
+	cores := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(fields[1])
+		switch strings.TrimSpace(fields[0]) {
+		case "processor":
+			hardwareInfo.FireExecutie++
+		case "model name", "Model":
+			if hardwareInfo.Procesor == "" {
+				hardwareInfo.Procesor = value
+			}
+		case "physical id":
+			physicalID = value
+		case "core id":
+			cores[physicalID+"/"+value] = true
+		case "cpu MHz":
+			if mhz == 0 {
+				mhz, _ = strconv.ParseFloat(value, 64)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("eroare la parsarea /proc/cpuinfo: %w", err)
+	}
+
+	// Unele arhitecturi (ARM, masini virtuale) nu raporteaza 'core id'
+	hardwareInfo.Nuclee = len(cores)
+	if hardwareInfo.Nuclee == 0 {
+		hardwareInfo.Nuclee = hardwareInfo.FireExecutie
+	}
+
+	// Frecventa maxima (in kHz), ca MaxClockSpeed din wmic; altfel frecventa curenta
+	maxFreq, err := strconv.ParseFloat(readTrimmed(filepath.Join(root, "sys/devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq")), 64)
+	if err == nil && maxFreq > 0 {
+		mhz = maxFreq / 1000
+	}
+	if mhz > 0 {
+		hardwareInfo.Frecventa = fmt.Sprintf("%.0f MHz", mhz)
+	} else {
+		hardwareInfo.Frecventa = "N/A"
+	}
+
+	return nil
+}
+
+// Functie pentru a citi /proc/meminfo
+func readLinuxMemInfo(root string, hardwareInfo *HardwareInfo) error {
+	file, err := os.Open(filepath.Join(root, "proc/meminfo"))
+	if err != nil {
+		return fmt.Errorf("eroare la citirea /proc/meminfo: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			totalKB, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("eroare la parsarea MemTotal: %w", err)
+			}
+			// MemTotal exclude memoria rezervata de kernel, deci rotunjim la GB
+			hardwareInfo.MemorieRAM = fmt.Sprintf("%.0f GB", math.Round(float64(totalKB)/(1024*1024)))
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("eroare la parsarea /proc/meminfo: %w", err)
+	}
+	return fmt.Errorf("MemTotal lipseste din /proc/meminfo")
+}
+
+// Functie pentru a citi informatii despre primul disc fizic din /sys/block
+func readLinuxDiskInfo(root string, hardwareInfo *HardwareInfo) {
+	hardwareInfo.TipStocare = "N/A"
+	hardwareInfo.CapacitateHDD = "N/A"
+
+	entries, err := os.ReadDir(filepath.Join(root, "sys/block"))
+	if err != nil {
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		block := filepath.Join(root, "sys/block", name)
+		// Dispozitivele virtuale (loop, ram, zram, dm-*, md*) nu au legatura 'device'
+		if _, err := os.Stat(filepath.Join(block, "device")); err != nil {
+			continue
+		}
+		if readTrimmed(filepath.Join(block, "removable")) == "1" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(name, "nvme"):
+			hardwareInfo.TipStocare = "SSD NVMe"
+		case readTrimmed(filepath.Join(block, "queue/rotational")) == "1":
+			hardwareInfo.TipStocare = "HDD"
+		default:
+			hardwareInfo.TipStocare = "SSD"
+		}
+
+		// Dimensiunea este exprimata in sectoare de 512 bytes
+		sectors, err := strconv.ParseUint(readTrimmed(filepath.Join(block, "size")), 10, 64)
+		if err == nil {
+			hardwareInfo.CapacitateHDD = fmt.Sprintf("%d GB", sectors*512/(1024*1024*1024))
+		}
+
+		hardwareInfo.ModelStocare = readTrimmed(filepath.Join(block, "device/model"))
+		return
+	}
+}
+
+// Functie pentru a obtine numele placilor video din /sys/class/drm
+func readLinuxGPUInfo(root string) string {
+	entries, err := os.ReadDir(filepath.Join(root, "sys/class/drm"))
+	if err != nil {
+		return "N/A"
+	}
+
+	var gpus []string
+	for _, entry := range entries {
+		name := entry.Name()
+		// Intrarile de forma 'card0-HDMI-A-1' sunt conectori, nu placi video
+		if !strings.HasPrefix(name, "card") || strings.Contains(name, "-") {
+			continue
+		}
+		device := filepath.Join(root, "sys/class/drm", name, "device")
+		vendor := readTrimmed(filepath.Join(device, "vendor"))
+		if vendor == "" {
+			continue
+		}
+		gpus = append(gpus, pciDeviceName(root, vendor, readTrimmed(filepath.Join(device, "device"))))
+	}
+	if len(gpus) == 0 {
+		return "N/A"
+	}
+	sort.Strings(gpus)
+	return strings.Join(gpus, ", ")
+}
+
+// Functie care traduce ID-urile PCI in nume, folosind baza de date pci.ids
+// daca exista, altfel lista de producatori cunoscuti
+func pciDeviceName(root, vendor, device string) string {
+	vendorID := strings.TrimPrefix(strings.ToLower(vendor), "0x")
+	deviceID := strings.TrimPrefix(strings.ToLower(device), "0x")
+
+	// Se foloseste primul fisier pci.ids gasit
+	for _, path := range []string{"usr/share/hwdata/pci.ids", "usr/share/misc/pci.ids", "usr/share/pci.ids"} {
+		name, err := lookupPCIIDs(filepath.Join(root, path), vendorID, deviceID)
+		if err != nil {
+			continue
+		}
+		if name != "" {
+			return name
+		}
+		break
+	}
+
+	if name, ok := pciVendors["0x"+vendorID]; ok {
+		return name + " [" + deviceID + "]"
+	}
+	return vendorID + ":" + deviceID
+}
+
+// Functie care cauta producatorul si dispozitivul in fisierul pci.ids dat
+// Intoarce "" daca producatorul nu exista in fisier
+func lookupPCIIDs(path, vendorID, deviceID string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var vendorName string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, "\t") {
+			// O linie fara tab incepe un producator nou
+			if vendorName != "" {
+				break
+			}
+			if strings.HasPrefix(line, vendorID+" ") {
+				vendorName = strings.TrimSpace(line[len(vendorID):])
+			}
+			continue
+		}
+		if vendorName != "" && strings.HasPrefix(line, "\t"+deviceID+" ") {
+			return vendorName + " " + strings.TrimSpace(line[len(deviceID)+1:]), nil
+		}
+	}
+	if vendorName != "" {
+		return vendorName + " [" + deviceID + "]", nil
+	}
+	return "", nil
+}
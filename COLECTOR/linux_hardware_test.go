@@ -0,0 +1,185 @@
+package colector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Functie care creeaza fisierele date sub radacina root
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Un laptop cu 2 nuclee si 4 fire, un card SD (amovibil) si un disc NVMe,
+// o placa video integrata si una dedicata
+var laptopTree = map[string]string{
+	"proc/cpuinfo": `processor	: 0
+model name	: Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz
+physical id	: 0
+core id		: 0
+cpu MHz		: 1800.000
+
+processor	: 1
+model name	: Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz
+physical id	: 0
+core id		: 1
+cpu MHz		: 1700.000
+
+processor	: 2
+model name	: Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz
+physical id	: 0
+core id		: 0
+
+processor	: 3
+model name	: Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz
+physical id	: 0
+core id		: 1
+`,
+	"sys/devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq": "3400000\n",
+	// 15,55 GB vizibili pentru un modul de 16 GB
+	"proc/meminfo": "MemTotal:       16303156 kB\nMemFree:         8123456 kB\n",
+
+	"sys/class/dmi/id/board_vendor": "LENOVO\n",
+	"sys/class/dmi/id/board_name":   "20L5000UUS\n",
+	"sys/class/dmi/id/board_serial": "L1HF8AB01CD\n",
+	"sys/class/dmi/id/bios_vendor":  "LENOVO\n",
+	"sys/class/dmi/id/bios_version": "N24ET75W (1.50 )\n",
+
+	// loop0 nu are legatura 'device', mmcblk0 este amovibil
+	"sys/block/loop0/size":              "0\n",
+	"sys/block/mmcblk0/removable":       "1\n",
+	"sys/block/mmcblk0/size":            "62333952\n",
+	"sys/block/mmcblk0/device/type":     "SD\n",
+	"sys/block/nvme0n1/removable":       "0\n",
+	"sys/block/nvme0n1/size":            "1953525168\n",
+	"sys/block/nvme0n1/device/model":    "Samsung SSD 980 PRO 1TB                 \n",
+	"sys/block/sda/removable":           "0\n",
+	"sys/block/sda/size":                "3907029168\n",
+	"sys/block/sda/queue/rotational":    "1\n",
+	"sys/block/sda/device/model":        "ST2000DM008-2FR1\n",
+	"sys/class/drm/card0/device/vendor": "0x8086\n",
+	"sys/class/drm/card0/device/device": "0x5917\n",
+	"sys/class/drm/card0-eDP-1/status":  "connected\n",
+	"sys/class/drm/card1/device/vendor": "0x10de\n",
+	"sys/class/drm/card1/device/device": "0x1c8d\n",
+	"sys/class/drm/renderD128/dev":      "226:128\n",
+
+	"usr/share/hwdata/pci.ids": `# Lista ID-urilor PCI
+8086  Intel Corporation
+	5912  HD Graphics 630
+	5917  UHD Graphics 620
+10de  NVIDIA Corporation
+	1c8d  GP107M [GeForce GTX 1050 Mobile]
+1002  Advanced Micro Devices, Inc. [AMD/ATI]
+`,
+}
+
+func TestLinuxHardwareInfo(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, laptopTree)
+
+	hardware, err := getLinuxHardwareInfo(root)
+	if err != nil {
+		t.Fatalf("getLinuxHardwareInfo: %v", err)
+	}
+	want := HardwareInfo{
+		Procesor:          "Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz",
+		Nuclee:            2,
+		FireExecutie:      4,
+		Frecventa:         "3400 MHz",
+		MemorieRAM:        "16 GB",
+		TipStocare:        "SSD NVMe",
+		CapacitateHDD:     "931 GB",
+		PlacaDeBaza:       "LENOVO 20L5000UUS",
+		PlacaVideo:        "Intel Corporation UHD Graphics 620, NVIDIA Corporation GP107M [GeForce GTX 1050 Mobile]",
+		ModelStocare:      "Samsung SSD 980 PRO 1TB",
+		BIOS:              "LENOVO N24ET75W (1.50 )",
+		SerialPlacaDeBaza: "L1HF8AB01CD",
+	}
+	if *hardware != want {
+		t.Errorf("getLinuxHardwareInfo:\n got  %+v\n want %+v", *hardware, want)
+	}
+}
+
+// Un server virtual: fara 'core id', fara cpufreq, fara DMI si fara pci.ids
+func TestLinuxHardwareInfoMinimal(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"proc/cpuinfo":                      "processor\t: 0\nmodel name\t: QEMU Virtual CPU\ncpu MHz\t\t: 2394.454\n\nprocessor\t: 1\nmodel name\t: QEMU Virtual CPU\n",
+		"proc/meminfo":                      "MemTotal:        3880084 kB\n",
+		"sys/block/sda/removable":           "0\n",
+		"sys/block/sda/size":                "104857600\n",
+		"sys/block/sda/queue/rotational":    "1\n",
+		"sys/block/sda/device/model":        "QEMU HARDDISK\n",
+		"sys/class/drm/card0/device/vendor": "0x1234\n",
+		"sys/class/drm/card0/device/device": "0x1111\n",
+	})
+
+	hardware, err := getLinuxHardwareInfo(root)
+	if err != nil {
+		t.Fatalf("getLinuxHardwareInfo: %v", err)
+	}
+	want := HardwareInfo{
+		Procesor:          "QEMU Virtual CPU",
+		Nuclee:            2,
+		FireExecutie:      2,
+		Frecventa:         "2394 MHz",
+		MemorieRAM:        "4 GB",
+		TipStocare:        "HDD",
+		CapacitateHDD:     "50 GB",
+		PlacaDeBaza:       "N/A",
+		PlacaVideo:        "QEMU [1111]",
+		ModelStocare:      "QEMU HARDDISK",
+		BIOS:              "N/A",
+		SerialPlacaDeBaza: "N/A",
+	}
+	if *hardware != want {
+		t.Errorf("getLinuxHardwareInfo:\n got  %+v\n want %+v", *hardware, want)
+	}
+}
+
+func TestLinuxHardwareInfoMissingMemInfo(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"proc/cpuinfo": "processor\t: 0\n",
+		"proc/meminfo": "MemFree:         8123456 kB\n",
+	})
+	_, err := getLinuxHardwareInfo(root)
+	if err == nil {
+		t.Fatal("getLinuxHardwareInfo: eroare asteptata pentru MemTotal lipsa")
+	}
+}
+
+func TestPCIDeviceName(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"usr/share/misc/pci.ids": laptopTree["usr/share/hwdata/pci.ids"]})
+
+	tests := []struct {
+		vendor, device, want string
+	}{
+		{"0x8086", "0x5912", "Intel Corporation HD Graphics 630"},
+		// Dispozitiv necunoscut al unui producator cunoscut
+		{"0x10de", "0x2204", "NVIDIA Corporation [2204]"},
+		{"0x1002", "0x73bf", "Advanced Micro Devices, Inc. [AMD/ATI] [73bf]"},
+		// Producator care lipseste din pci.ids, dar este in lista interna
+		{"0x15ad", "0x0405", "VMware [0405]"},
+		{"0xabcd", "0x0001", "abcd:0001"},
+	}
+	for _, tc := range tests {
+		if got := pciDeviceName(root, tc.vendor, tc.device); got != tc.want {
+			t.Errorf("pciDeviceName(%s, %s) = %q, asteptat %q", tc.vendor, tc.device, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package colector
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Radacina sistemului de fisiere din care se citesc informatiile pe Linux
+// Poate fi schimbata cu optiunea -sysroot (de ex. catre un director de test)
+var SysRoot = "/"
+
+// Fisiere scrise o singura data de instalatoarele uzuale; data modificarii
+// primului gasit este folosita ca data instalarii sistemului de operare
+// Directoarele (var/log/installer, lost+found) si jurnalele care cresc la fiecare
+// actualizare nu sunt folosite, pentru ca data lor se schimba dupa instalare
+var linuxInstallerArtifacts = []string{
+	"var/log/installer/syslog",  // Debian, Ubuntu
+	"root/anaconda-ks.cfg",      // Fedora, RHEL, CentOS
+	"var/lib/YaST2/install.inf", // openSUSE
+}
+
+// Jurnalul pacman (Arch) este completat la fiecare tranzactie; data instalarii
+// este cea de pe prima linie, scrisa de pacstrap
+const pacmanLog = "var/log/pacman.log"
+
+// Formatele datei de pe liniile jurnalului pacman: cel actual, cu fusul orar,
+// si cel vechi, in ora locala
+var pacmanTimeLayouts = []string{"2006-01-02T15:04:05-0700", "2006-01-02 15:04"}
+
+// Functie pentru a obtine informatii despre sistemul de operare pe Linux
+// Toate informatiile sunt citite din fisiere aflate sub radacina data
+func getLinuxOSInfo(root string) (*OSInfo, error) {
+	osInfo := &OSInfo{}
+
+	// Numele si versiunea distributiei din os-release
+	release, err := parseOSRelease(root)
+	if err != nil {
+		return nil, err
+	}
+	osInfo.Nume = release["NAME"]
+	if osInfo.Nume == "" {
+		osInfo.Nume = "Linux"
+	}
+	osInfo.Versiune = release["VERSION"]
+	if osInfo.Versiune == "" {
+		osInfo.Versiune = release["VERSION_ID"]
+	}
+	if osInfo.Versiune == "" {
+		osInfo.Versiune = "N/A"
+	}
+
+	// Versiunea kernel-ului (echivalentul 'uname -r')
+	osInfo.Kernel = readTrimmed(filepath.Join(root, "proc/sys/kernel/osrelease"))
+	if osInfo.Kernel == "" {
+		osInfo.Kernel = "N/A"
+	}
+
+	// Arhitectura masinii (echivalentul 'uname -m'); fisierul exista doar pe
+	// kernel-uri recente, altfel folosim arhitectura pentru care a fost compilat agentul
+	osInfo.Arhitectura = readTrimmed(filepath.Join(root, "proc/sys/kernel/arch"))
+	if osInfo.Arhitectura == "" {
+		osInfo.Arhitectura = runtime.GOARCH
+	}
+
+	// Data instalarii din fisierele lasate de instalator
+	osInfo.DataInstalarii = linuxInstallDate(root)
+
+	osInfo.Licenta = "N/A"
+
+	return osInfo, nil
+}
+
+// Functie care intoarce data instalarii sistemului de operare, sau "N/A"
+func linuxInstallDate(root string) string {
+	for _, artifact := range linuxInstallerArtifacts {
+		info, err := os.Stat(filepath.Join(root, artifact))
+		if err == nil && info.Mode().IsRegular() {
+			return info.ModTime().Format("2006-01-02 15:04:05")
+		}
+	}
+	if installed, ok := pacmanLogStart(filepath.Join(root, pacmanLog)); ok {
+		return installed.Format("2006-01-02 15:04:05")
+	}
+	return "N/A"
+}
+
+// Functie care citeste momentul de pe prima linie a jurnalului pacman, de forma
+// "[2023-03-01T10:20:30+0100] [PACMAN] ..." sau "[2023-03-01 10:20] ..."
+func pacmanLogStart(path string) (time.Time, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return time.Time{}, false
+	}
+	line := scanner.Text()
+	end := strings.Index(line, "]")
+	if !strings.HasPrefix(line, "[") || end < 0 {
+		return time.Time{}, false
+	}
+	for _, layout := range pacmanTimeLayouts {
+		installed, err := time.ParseInLocation(layout, line[1:end], time.Local)
+		if err == nil {
+			return installed.Local(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Functie pentru a citi fisierul os-release (format CHEIE=valoare)
+func parseOSRelease(root string) (map[string]string, error) {
+	var file *os.File
+	var err error
+	for _, path := range []string{"etc/os-release", "usr/lib/os-release"} {
+		file, err = os.Open(filepath.Join(root, path))
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea fisierului os-release: %w", err)
+	}
+	defer file.Close()
+
+	release := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(fields[1])
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `"'`)
+		}
+		release[strings.TrimSpace(fields[0])] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("eroare la parsarea fisierului os-release: %w", err)
+	}
+
+	return release, nil
+}
+
+// Functie care citeste un fisier de o singura valoare (ca in /proc sau /sys)
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
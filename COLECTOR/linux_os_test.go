@@ -0,0 +1,181 @@
+package colector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// Functie care schimba data modificarii unui fisier sau director de sub root
+func setModTime(t *testing.T, root, name string, modTime time.Time) {
+	t.Helper()
+	err := os.Chtimes(filepath.Join(root, name), modTime, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLinuxOSInfo(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"etc/os-release": `NAME="Ubuntu"
+VERSION="22.04.4 LTS (Jammy Jellyfish)"
+VERSION_ID="22.04"
+`,
+		"proc/sys/kernel/osrelease": "6.5.0-41-generic\n",
+		"proc/sys/kernel/arch":      "x86_64\n",
+		"var/log/installer/syslog":  "installer\n",
+		"var/log/installer/.keep":   "",
+		"lost+found/.keep":          "",
+	})
+	installed := time.Date(2023, 3, 1, 10, 20, 30, 0, time.UTC)
+	setModTime(t, root, "var/log/installer/syslog", installed)
+	// Directoarele se modifica dupa instalare si nu sunt folosite
+	setModTime(t, root, "var/log/installer", installed.Add(time.Hour))
+	setModTime(t, root, "lost+found", installed.Add(-time.Hour))
+
+	osInfo, err := getLinuxOSInfo(root)
+	if err != nil {
+		t.Fatalf("getLinuxOSInfo: %v", err)
+	}
+	want := OSInfo{
+		Nume:           "Ubuntu",
+		Versiune:       "22.04.4 LTS (Jammy Jellyfish)",
+		Arhitectura:    "x86_64",
+		DataInstalarii: installed.Local().Format("2006-01-02 15:04:05"),
+		Licenta:        "N/A",
+		Kernel:         "6.5.0-41-generic",
+	}
+	if *osInfo != want {
+		t.Errorf("getLinuxOSInfo:\n got  %+v\n want %+v", *osInfo, want)
+	}
+}
+
+// Fara osrelease si arch in /proc, cu os-release doar in /usr/lib si fara VERSION
+func TestLinuxOSInfoFallbacks(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"usr/lib/os-release":   "NAME=Fedora Linux\nVERSION_ID=40\n",
+		"root/anaconda-ks.cfg": "#version=RHEL9\n",
+	})
+	installed := time.Date(2024, 5, 20, 7, 0, 0, 0, time.UTC)
+	setModTime(t, root, "root/anaconda-ks.cfg", installed)
+
+	osInfo, err := getLinuxOSInfo(root)
+	if err != nil {
+		t.Fatalf("getLinuxOSInfo: %v", err)
+	}
+	want := OSInfo{
+		Nume:           "Fedora Linux",
+		Versiune:       "40",
+		Arhitectura:    runtime.GOARCH,
+		DataInstalarii: installed.Local().Format("2006-01-02 15:04:05"),
+		Licenta:        "N/A",
+		Kernel:         "N/A",
+	}
+	if *osInfo != want {
+		t.Errorf("getLinuxOSInfo:\n got  %+v\n want %+v", *osInfo, want)
+	}
+}
+
+// Pe Arch data instalarii vine de pe prima linie a jurnalului pacman, care
+// ramane aceeasi cand jurnalul este completat la actualizari
+func TestLinuxOSInfoPacmanLog(t *testing.T) {
+	tests := []struct {
+		name, firstLine string
+		want            time.Time
+	}{
+		{"format actual", "[2023-03-01T10:20:30+0000] [PACMAN] Running 'pacman -Sy --root /mnt base'",
+			time.Date(2023, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"format vechi", "[2019-07-14 08:05] [PACMAN] Running 'pacman -r /mnt -Sy base'",
+			time.Date(2019, 7, 14, 8, 5, 0, 0, time.Local)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, map[string]string{
+				"etc/os-release":     "NAME=\"Arch Linux\"\n",
+				"var/log/pacman.log": tc.firstLine + "\n",
+			})
+			want := tc.want.Local().Format("2006-01-02 15:04:05")
+
+			for _, modTime := range []time.Time{time.Now().Add(-time.Hour), time.Now()} {
+				// O actualizare adauga linii si schimba data modificarii jurnalului
+				file, err := os.OpenFile(filepath.Join(root, "var/log/pacman.log"), os.O_APPEND|os.O_WRONLY, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				file.WriteString("[2024-05-20T07:00:00+0000] [ALPM] upgraded linux (6.8.9 -> 6.9.1)\n")
+				file.Close()
+				setModTime(t, root, "var/log/pacman.log", modTime)
+
+				osInfo, err := getLinuxOSInfo(root)
+				if err != nil {
+					t.Fatalf("getLinuxOSInfo: %v", err)
+				}
+				if osInfo.DataInstalarii != want {
+					t.Errorf("DataInstalarii = %q, asteptat %q", osInfo.DataInstalarii, want)
+				}
+			}
+		})
+	}
+}
+
+// Un os-release fara nume si versiune, fara fisiere ale instalatorului; directoarele
+// si un jurnal pacman fara data nu sunt folosite
+func TestLinuxOSInfoUnknown(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"etc/os-release":     "ID=custom\n",
+		"var/log/anaconda/x": "",
+		"lost+found/.keep":   "",
+		"var/log/pacman.log": "linie fara data\n",
+	})
+
+	osInfo, err := getLinuxOSInfo(root)
+	if err != nil {
+		t.Fatalf("getLinuxOSInfo: %v", err)
+	}
+	if osInfo.Nume != "Linux" || osInfo.Versiune != "N/A" || osInfo.DataInstalarii != "N/A" {
+		t.Errorf("getLinuxOSInfo = %+v", *osInfo)
+	}
+}
+
+func TestLinuxOSInfoMissingOSRelease(t *testing.T) {
+	_, err := getLinuxOSInfo(t.TempDir())
+	if err == nil {
+		t.Fatal("getLinuxOSInfo: eroare asteptata pentru os-release lipsa")
+	}
+}
+
+func TestParseOSReleaseQuoting(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"etc/os-release": `# comentariu
+NAME="Debian GNU/Linux"
+PRETTY_NAME="Debian \"bookworm\" 12"
+VERSION='12 (bookworm)'
+ID=debian
+ HOME_URL = "https://www.debian.org/"
+linie fara egal
+`,
+	})
+
+	release, err := parseOSRelease(root)
+	if err != nil {
+		t.Fatalf("parseOSRelease: %v", err)
+	}
+	want := map[string]string{
+		"NAME":        "Debian GNU/Linux",
+		"PRETTY_NAME": `Debian "bookworm" 12`,
+		"VERSION":     "12 (bookworm)",
+		"ID":          "debian",
+		"HOME_URL":    "https://www.debian.org/",
+	}
+	if !reflect.DeepEqual(release, want) {
+		t.Errorf("parseOSRelease:\n got  %v\n want %v", release, want)
+	}
+}
@@ -0,0 +1,269 @@
+package colector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expresii pentru extragerea versiunii si producatorului din metainfo-ul flatpak
+var (
+	flatpakReleaseRegexp   = regexp.MustCompile(`<release[^>]*\sversion="([^"]+)"`)
+	flatpakDeveloperRegexp = regexp.MustCompile(`<developer_name[^>]*>([^<]+)</developer_name>`)
+)
+
+// Sursele programelor instalate pe Linux
+var linuxProgramSources = []struct {
+	name string
+	get  func(context.Context, string) ([]ProgramInfo, error)
+}{
+	{"dpkg", getDpkgPrograms},
+	{"rpm", getRpmPrograms},
+	{"snap", getSnapPrograms},
+	{"flatpak", getFlatpakPrograms},
+}
+
+// Functie pentru a obtine programele instalate pe Linux
+// Se combina pachetele dpkg, rpm, snap si flatpak; sursele care nu exista
+// pe sistem sunt ignorate. Daca o sursa nu poate fi citita, se pastreaza
+// programele din celelalte surse; eroarea este intoarsa doar daca nu a fost
+// gasit niciun program
+func getLinuxInstalledPrograms(ctx context.Context, root string) ([]ProgramInfo, error) {
+	var programs []ProgramInfo
+	var errs []error
+
+	for _, source := range linuxProgramSources {
+		found, err := source.get(ctx, root)
+		if err != nil {
+			fmt.Printf("Eroare la citirea pachetelor %s: %v\n", source.name, err)
+			errs = append(errs, err)
+			continue
+		}
+		programs = append(programs, found...)
+	}
+
+	if len(programs) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return programs, nil
+}
+
+// Functie pentru a citi pachetele din /var/lib/dpkg/status (Debian, Ubuntu)
+func getDpkgPrograms(ctx context.Context, root string) ([]ProgramInfo, error) {
+	file, err := os.Open(filepath.Join(root, "var/lib/dpkg/status"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea /var/lib/dpkg/status: %w", err)
+	}
+	defer file.Close()
+
+	var programs []ProgramInfo
+	var program ProgramInfo
+	var status, arch string
+	addProgram := func() {
+		// Doar pachetele instalate complet ('install ok installed')
+		if program.Nume != "" && strings.HasSuffix(status, " installed") {
+			program.Sursa = "dpkg"
+			program.DataInstalare = dpkgInstallDate(root, program.Nume, arch)
+			programs = append(programs, program)
+		}
+		program = ProgramInfo{}
+		status, arch = "", ""
+	}
+
+	scanner := bufio.NewScanner(file)
+	// Campul 'Description' poate avea linii lungi
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// Finalizam informatiile pentru un pachet si adaugam in lista
+			addProgram()
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue // continuarea unui camp pe mai multe linii
+		}
+		fields := strings.SplitN(line, ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(fields[1])
+		switch fields[0] {
+		case "Package":
+			program.Nume = value
+		case "Version":
+			program.Versiune = value
+		case "Maintainer":
+			program.Producator = value
+		case "Status":
+			status = value
+		case "Architecture":
+			arch = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("eroare la parsarea /var/lib/dpkg/status: %w", err)
+	}
+	// Adaugam ultimul pachet (daca exista)
+	addProgram()
+
+	return programs, nil
+}
+
+// Functie care deduce data instalarii unui pachet dpkg din lista lui de fisiere
+func dpkgInstallDate(root, name, arch string) string {
+	info := filepath.Join(root, "var/lib/dpkg/info")
+	for _, list := range []string{name + ":" + arch + ".list", name + ".list"} {
+		stat, err := os.Stat(filepath.Join(info, list))
+		if err == nil {
+			return stat.ModTime().Format("2006-01-02 15:04:05")
+		}
+	}
+	return "N/A"
+}
+
+// Functie pentru a obtine pachetele din baza de date rpm (Fedora, RHEL, openSUSE)
+func getRpmPrograms(ctx context.Context, root string) ([]ProgramInfo, error) {
+	if _, err := os.Stat(filepath.Join(root, "var/lib/rpm")); err != nil {
+		return nil, nil
+	}
+
+	out, err := Runner.Run(ctx, "rpm", "--root", root, "-qa", "--queryformat", `%{NAME}\t%{VERSION}-%{RELEASE}\t%{VENDOR}\t%{INSTALLTIME}\n`)
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, nil // baza de date exista, dar utilitarul rpm nu este instalat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'rpm -qa': %w", err)
+	}
+	return parseRpmQuery(string(out)), nil
+}
+
+// Functie pentru a parsa rezultatul 'rpm -qa --queryformat' (campuri separate prin tab)
+func parseRpmQuery(out string) []ProgramInfo {
+	var programs []ProgramInfo
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) < 4 || fields[0] == "" || fields[0] == "gpg-pubkey" {
+			continue
+		}
+		program := ProgramInfo{
+			Nume:          fields[0],
+			Versiune:      fields[1],
+			Producator:    fields[2],
+			DataInstalare: "N/A",
+			Sursa:         "rpm",
+		}
+		if program.Producator == "(none)" {
+			program.Producator = ""
+		}
+		if seconds, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+			program.DataInstalare = time.Unix(seconds, 0).Format("2006-01-02 15:04:05")
+		}
+		programs = append(programs, program)
+	}
+	return programs
+}
+
+// Functie pentru a obtine pachetele snap din /snap/<nume>/current/meta/snap.yaml
+func getSnapPrograms(ctx context.Context, root string) ([]ProgramInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(root, "snap"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea directorului /snap: %w", err)
+	}
+
+	var programs []ProgramInfo
+	for _, entry := range entries {
+		current := filepath.Join(root, "snap", entry.Name(), "current")
+		data, err := os.ReadFile(filepath.Join(current, "meta/snap.yaml"))
+		if err != nil {
+			continue // de ex. /snap/bin
+		}
+
+		program := ProgramInfo{Nume: entry.Name(), DataInstalare: "N/A", Sursa: "snap"}
+		for _, line := range strings.Split(string(data), "\n") {
+			// Ne intereseaza doar cheile de pe primul nivel
+			if strings.HasPrefix(line, " ") {
+				continue
+			}
+			fields := strings.SplitN(line, ":", 2)
+			if len(fields) != 2 {
+				continue
+			}
+			value := strings.Trim(strings.TrimSpace(fields[1]), `"'`)
+			switch fields[0] {
+			case "name":
+				program.Nume = value
+			case "version":
+				program.Versiune = value
+			}
+		}
+		if stat, err := os.Stat(current); err == nil {
+			program.DataInstalare = stat.ModTime().Format("2006-01-02 15:04:05")
+		}
+		programs = append(programs, program)
+	}
+
+	return programs, nil
+}
+
+// Functie pentru a obtine aplicatiile flatpak instalate la nivel de sistem
+func getFlatpakPrograms(ctx context.Context, root string) ([]ProgramInfo, error) {
+	apps := filepath.Join(root, "var/lib/flatpak/app")
+	entries, err := os.ReadDir(apps)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea directorului flatpak: %w", err)
+	}
+
+	var programs []ProgramInfo
+	for _, entry := range entries {
+		active := filepath.Join(apps, entry.Name(), "current/active")
+		stat, err := os.Stat(active)
+		if err != nil {
+			continue
+		}
+
+		program := ProgramInfo{
+			Nume:          entry.Name(),
+			Versiune:      "N/A",
+			DataInstalare: stat.ModTime().Format("2006-01-02 15:04:05"),
+			Sursa:         "flatpak",
+		}
+		for _, dir := range []string{"files/share/metainfo", "files/share/appdata"} {
+			data, err := os.ReadFile(filepath.Join(active, dir, entry.Name()+".metainfo.xml"))
+			if err != nil {
+				data, err = os.ReadFile(filepath.Join(active, dir, entry.Name()+".appdata.xml"))
+			}
+			if err != nil {
+				continue
+			}
+			// Prima versiune listata in <releases> este cea mai recenta
+			if match := flatpakReleaseRegexp.FindSubmatch(data); match != nil {
+				program.Versiune = string(match[1])
+			}
+			if match := flatpakDeveloperRegexp.FindSubmatch(data); match != nil {
+				program.Producator = strings.TrimSpace(string(match[1]))
+			}
+			break
+		}
+		programs = append(programs, program)
+	}
+
+	return programs, nil
+}
@@ -0,0 +1,157 @@
+package colector
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Radacinile de test sunt date cu '/', asa cum apar in comenzile din corpus
+const (
+	debianRoot = "testdata/radacini/debian"
+	fedoraRoot = "testdata/radacini/fedora"
+	mixtRoot   = "testdata/radacini/mixt"
+)
+
+// Functie care reda comenzile din corpusul dat, pana la sfarsitul testului
+func useReplayRunner(t *testing.T, corpus string) {
+	t.Helper()
+	runner := Runner
+	t.Cleanup(func() {
+		Runner = runner
+	})
+	Runner = ReplayRunner{Dir: filepath.Join("testdata", "comenzi", corpus)}
+}
+
+// Functie care formateaza un moment ca data instalarii unui program
+func installDate(at time.Time) string {
+	return at.Local().Format("2006-01-02 15:04:05")
+}
+
+func TestDpkgPrograms(t *testing.T) {
+	installed := time.Date(2023, 4, 21, 8, 15, 0, 0, time.UTC)
+	setModTime(t, debianRoot, "var/lib/dpkg/info/bash.list", installed)
+
+	programs, err := getDpkgPrograms(context.Background(), debianRoot)
+	if err != nil {
+		t.Fatalf("getDpkgPrograms: %v", err)
+	}
+	// Pachetele dezinstalate sau configurate partial sunt ignorate; liniile de
+	// continuare ale descrierii nu sunt campuri
+	want := []ProgramInfo{
+		{Nume: "bash", Versiune: "5.1-6ubuntu1.1", Producator: "Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>", DataInstalare: installDate(installed), Sursa: "dpkg"},
+		{Nume: "libc6", Versiune: "2.35-0ubuntu3.8", Producator: "Ubuntu Developers <ubuntu-devel-discuss@lists.ubuntu.com>", DataInstalare: "N/A", Sursa: "dpkg"},
+		{Nume: "python3-apt", Versiune: "2.4.0ubuntu3", Producator: "APT Development Team <deity@lists.debian.org>", DataInstalare: "N/A", Sursa: "dpkg"},
+	}
+	if !reflect.DeepEqual(programs, want) {
+		t.Errorf("getDpkgPrograms:\n got  %+v\n want %+v", programs, want)
+	}
+}
+
+// Pachetele Multi-Arch: same au lista de fisiere <nume>:<arhitectura>.list
+// Numele nu poate fi pastrat in testdata (':' nu este permis pe Windows)
+func TestDpkgInstallDateMultiArch(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"var/lib/dpkg/info/libc6:amd64.list": "/.\n",
+		"var/lib/dpkg/info/libc6.list":       "/.\n",
+	})
+	installed := time.Date(2024, 1, 9, 17, 0, 0, 0, time.UTC)
+	setModTime(t, root, "var/lib/dpkg/info/libc6:amd64.list", installed)
+	setModTime(t, root, "var/lib/dpkg/info/libc6.list", installed.Add(time.Hour))
+
+	if got := dpkgInstallDate(root, "libc6", "amd64"); got != installDate(installed) {
+		t.Errorf("dpkgInstallDate = %q, asteptat %q", got, installDate(installed))
+	}
+	if got := dpkgInstallDate(root, "lipsa", "amd64"); got != "N/A" {
+		t.Errorf("dpkgInstallDate = %q, asteptat N/A", got)
+	}
+}
+
+func TestSnapPrograms(t *testing.T) {
+	core := time.Date(2024, 4, 8, 12, 0, 0, 0, time.UTC)
+	firefox := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	setModTime(t, debianRoot, "snap/core22/current", core)
+	setModTime(t, debianRoot, "snap/firefox/current", firefox)
+
+	programs, err := getSnapPrograms(context.Background(), debianRoot)
+	if err != nil {
+		t.Fatalf("getSnapPrograms: %v", err)
+	}
+	// /snap/bin nu are snap.yaml; versiunile aplicatiilor (indentate) sunt ignorate
+	want := []ProgramInfo{
+		{Nume: "core22", Versiune: "20240408", DataInstalare: installDate(core), Sursa: "snap"},
+		{Nume: "firefox", Versiune: "125.0.2-1", DataInstalare: installDate(firefox), Sursa: "snap"},
+	}
+	if !reflect.DeepEqual(programs, want) {
+		t.Errorf("getSnapPrograms:\n got  %+v\n want %+v", programs, want)
+	}
+}
+
+func TestFlatpakPrograms(t *testing.T) {
+	apps := "var/lib/flatpak/app/"
+	fara := time.Date(2024, 2, 2, 10, 0, 0, 0, time.UTC)
+	gimp := time.Date(2024, 5, 3, 11, 0, 0, 0, time.UTC)
+	vlc := time.Date(2024, 6, 6, 12, 0, 0, 0, time.UTC)
+	setModTime(t, debianRoot, apps+"com.example.FaraMetainfo/current/active", fara)
+	setModTime(t, debianRoot, apps+"org.gimp.GIMP/current/active", gimp)
+	setModTime(t, debianRoot, apps+"org.videolan.VLC/current/active", vlc)
+
+	programs, err := getFlatpakPrograms(context.Background(), debianRoot)
+	if err != nil {
+		t.Fatalf("getFlatpakPrograms: %v", err)
+	}
+	want := []ProgramInfo{
+		{Nume: "com.example.FaraMetainfo", Versiune: "N/A", DataInstalare: installDate(fara), Sursa: "flatpak"},
+		// Prima versiune din <releases> este cea mai recenta
+		{Nume: "org.gimp.GIMP", Versiune: "2.10.38", Producator: "The GIMP team", DataInstalare: installDate(gimp), Sursa: "flatpak"},
+		// Fisierul vechi appdata.xml, cu atributele in alta ordine
+		{Nume: "org.videolan.VLC", Versiune: "3.0.21", Producator: "VideoLAN", DataInstalare: installDate(vlc), Sursa: "flatpak"},
+	}
+	if !reflect.DeepEqual(programs, want) {
+		t.Errorf("getFlatpakPrograms:\n got  %+v\n want %+v", programs, want)
+	}
+}
+
+func TestRpmPrograms(t *testing.T) {
+	useReplayRunner(t, "linux-fedora40-rpm")
+
+	programs, err := getRpmPrograms(context.Background(), fedoraRoot)
+	if err != nil {
+		t.Fatalf("getRpmPrograms: %v", err)
+	}
+	// Cheile gpg-pubkey nu sunt programe; '(none)' inseamna valoare lipsa
+	want := []ProgramInfo{
+		{Nume: "bash", Versiune: "5.2.26-3.fc40", Producator: "Fedora Project", DataInstalare: installDate(time.Unix(1714557600, 0)), Sursa: "rpm"},
+		{Nume: "kernel-core", Versiune: "6.8.9-300.fc40", Producator: "Fedora Project", DataInstalare: installDate(time.Unix(1715162400, 0)), Sursa: "rpm"},
+		{Nume: "google-chrome-stable", Versiune: "124.0.6367.155-1", Producator: "Google Inc.", DataInstalare: installDate(time.Unix(1715600000, 0)), Sursa: "rpm"},
+		{Nume: "local-tool", Versiune: "1.0-1", DataInstalare: "N/A", Sursa: "rpm"},
+	}
+	if !reflect.DeepEqual(programs, want) {
+		t.Errorf("getRpmPrograms:\n got  %+v\n want %+v", programs, want)
+	}
+}
+
+// Daca o sursa nu poate fi citita, programele din celelalte surse sunt pastrate
+func TestLinuxInstalledProgramsPartial(t *testing.T) {
+	useReplayRunner(t, "linux-rpm-eroare")
+
+	programs, err := getLinuxInstalledPrograms(context.Background(), mixtRoot)
+	if err != nil {
+		t.Fatalf("getLinuxInstalledPrograms: %v", err)
+	}
+	want := []ProgramInfo{
+		{Nume: "alien", Versiune: "8.95.6", Producator: "Debian QA Group <packages@qa.debian.org>", DataInstalare: "N/A", Sursa: "dpkg"},
+	}
+	if !reflect.DeepEqual(programs, want) {
+		t.Errorf("getLinuxInstalledPrograms:\n got  %+v\n want %+v", programs, want)
+	}
+
+	// Fara alte programe, eroarea rpm este intoarsa, nu o lista goala
+	_, err = getLinuxInstalledPrograms(context.Background(), fedoraRoot)
+	if err == nil {
+		t.Error("getLinuxInstalledPrograms: eroare asteptata cand singura sursa nu poate fi citita")
+	}
+}
@@ -0,0 +1,48 @@
+package colector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shirou/gopsutil/cpu"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/net"
+)
+
+// Structura pentru informatii live despre sistem
+type LiveSystemInfo struct {
+	UtilizareCPU      float64 `json:"utilizare_cpu"`
+	UtilizareRAM      float64 `json:"utilizare_ram"`
+	TraficTrimis      uint64  `json:"trafic_retea_bytes_trimisi"`
+	TraficReceptionat uint64  `json:"trafic_retea_bytes_primiti"`
+}
+
+// Functie pentru a obtine informatii live despre sistem
+func GetLiveSystemInfo(ctx context.Context) (*LiveSystemInfo, error) {
+	liveInfo := &LiveSystemInfo{}
+
+	// CPU Usage
+	cpuUsage, err := cpu.PercentWithContext(ctx, time.Second, true)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la obținerea utilizării CPU: %w", err)
+	}
+	liveInfo.UtilizareCPU = cpuUsage[0]
+
+	// RAM Usage
+	memInfo, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la obținerea utilizării RAM: %w", err)
+	}
+	liveInfo.UtilizareRAM = memInfo.UsedPercent
+
+	// Network Usage
+	netIO, err := net.IOCountersWithContext(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la obținerea utilizării rețelei: %w", err)
+	}
+	liveInfo.TraficReceptionat = netIO[0].BytesRecv
+	liveInfo.TraficTrimis = netIO[0].BytesSent
+
+	return liveInfo, nil
+}
@@ -0,0 +1,99 @@
+package colector
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Structura pentru informatii despre sistemul de operare
+type OSInfo struct {
+	Nume           string `json:"nume"`
+	Versiune       string `json:"versiune"`
+	Arhitectura    string `json:"arhitectura"`
+	DataInstalarii string `json:"data_instalarii"`
+	Licenta        string `json:"licenta"`
+	Kernel         string `json:"kernel,omitempty"`
+}
+
+// Functie pentru a obtine informatii despre sistemul de operare
+func GetOSInfo(ctx context.Context) (*OSInfo, error) {
+	osInfo := &OSInfo{}
+
+	// Obtine numele si versiunea sistemului de operare
+	switch TargetOS {
+	case "windows":
+		out, err := Runner.Run(ctx, "cmd", "/c", "ver")
+		if err != nil {
+			return nil, fmt.Errorf("eroare la executarea comenzii 'ver': %w", err)
+		}
+		osInfo.Nume = strings.TrimSpace(string(out))
+
+		// Obtine arhitectura sistemului de operare
+		osInfo.Arhitectura = runtime.GOARCH
+
+		// Obtine data instalarii sistemului de operare (pentru Windows)
+		out, err = Runner.Run(ctx, "cmd", "/c", "wmic os get InstallDate /VALUE")
+		if err != nil {
+			return nil, fmt.Errorf("eroare la executarea comenzii 'wmic os get InstallDate': %w", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(out)), "=")
+		if len(lines) > 1 {
+			dateString := strings.TrimSpace(lines[1])
+
+			// Soluția 1: Ajustarea formatului datei
+			// dataInstalare, err := time.Parse("20060102150405.000000-0700", dateString) // Formatul corect pentru fusul orar
+			// if err != nil {
+			// 	return nil, fmt.Errorf("eroare la parsarea datei de instalare: %w", err)
+			// }
+
+			// Soluția 2: Eliminarea fusului orar
+			if strings.Contains(dateString, "+") {
+				parts := strings.Split(dateString, "+")
+				dateString = parts[0] // Păstrează doar partea de dată
+			}
+
+			// Parsare DataInstalarii în format time.Time (fără fus orar)
+			dataInstalare, err := time.Parse("20060102150405.000000", dateString)
+			if err != nil {
+				return nil, fmt.Errorf("eroare la parsarea datei de instalare: %w", err)
+			}
+
+			// Formatare DataInstalarii în ISO 8601
+			osInfo.DataInstalarii = dataInstalare.Format("2006-01-02 15:04:05")
+		}
+
+		// Obtine licenta sistemului de operare (daca este cazul)
+		// Implementati aici logica specifica pentru licenta Windows
+		osInfo.Licenta = "N/A"
+
+	case "linux":
+		// Informatiile sunt citite din fisiere (os-release, /proc), sub radacina SysRoot
+		return getLinuxOSInfo(SysRoot)
+
+	case "darwin":
+		// Implementati logica pentru macOS
+		out, err := Runner.Run(ctx, "sw_vers", "-productName")
+		if err != nil {
+			return nil, fmt.Errorf("eroare la executarea comenzii 'sw_vers -productName': %w", err)
+		}
+		osInfo.Nume = strings.TrimSpace(string(out))
+
+		out, err = Runner.Run(ctx, "sw_vers", "-productVersion")
+		if err != nil {
+			return nil, fmt.Errorf("eroare la executarea comenzii 'sw_vers -productVersion': %w", err)
+		}
+		osInfo.Versiune = strings.TrimSpace(string(out))
+
+		osInfo.Arhitectura = runtime.GOARCH
+		osInfo.DataInstalarii = "N/A" // Exemplu simplificat pentru macOS
+		osInfo.Licenta = "N/A"
+
+	default:
+		return nil, fmt.Errorf("sistem de operare neacceptat: %s", TargetOS)
+	}
+
+	return osInfo, nil
+}
@@ -0,0 +1,234 @@
+package colector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// Functie care ruleaza colectoarele Windows pe corpusul din directorul dat,
+// pana la sfarsitul testului
+func useWindowsCorpus(t *testing.T, dir string) {
+	t.Helper()
+	runner, targetOS := Runner, TargetOS
+	t.Cleanup(func() {
+		Runner, TargetOS = runner, targetOS
+	})
+	Runner = ReplayRunner{Dir: dir}
+	TargetOS = "windows"
+}
+
+// Rezultatele asteptate pentru fiecare corpus din testdata/comenzi
+var windowsCorpora = []struct {
+	corpus      string
+	hardware    HardwareInfo
+	os          OSInfo
+	programs    []ProgramInfo
+	security    string
+	securityErr bool
+}{
+	{
+		// Un singur disc NVMe: PowerShell intoarce un obiect JSON
+		corpus: "windows10-desktop-nvme",
+		hardware: HardwareInfo{
+			Procesor:          "Intel(R) Core(TM) i7-9700 CPU @ 3.00GHz",
+			Nuclee:            8,
+			FireExecutie:      8,
+			Frecventa:         "3000 MHz",
+			MemorieRAM:        "16 GB",
+			TipStocare:        "SSD",
+			CapacitateHDD:     "931 GB",
+			PlacaDeBaza:       "ASUSTeK COMPUTER INC. PRIME B365M-A",
+			PlacaVideo:        "Intel(R) UHD Graphics 630",
+			ModelStocare:      "Samsung SSD 970 EVO Plus 1TB",
+			SerialPlacaDeBaza: "190949528800914",
+		},
+		os: OSInfo{
+			Nume:           "Microsoft Windows [Version 10.0.19045.4529]",
+			Arhitectura:    runtime.GOARCH,
+			DataInstalarii: "2023-01-15 09:30:12",
+			Licenta:        "N/A",
+		},
+		programs: []ProgramInfo{
+			{Nume: "Microsoft Visual C++ 2019 X64 Minimum Runtime - 14.29.30133", Versiune: "14.29.30133", Producator: "Microsoft Corporation", DataInstalare: "20230116", Sursa: "wmic"},
+			{Nume: "7-Zip 22.01 (x64 edition)", Versiune: "22.01.00.0", Producator: "Igor Pavlov", DataInstalare: "20230302", Sursa: "wmic"},
+			{Nume: "Google Chrome", Versiune: "120.0.6099.217", Producator: "Google LLC", DataInstalare: "20240110", Sursa: "wmic"},
+		},
+		security: "Windows Defender",
+	},
+	{
+		// Doua discuri NVMe: PowerShell intoarce un array JSON, se foloseste primul disc
+		corpus: "windows11-laptop-doua-nvme",
+		hardware: HardwareInfo{
+			Procesor:          "AMD Ryzen 7 PRO 5850U with Radeon Graphics",
+			Nuclee:            8,
+			FireExecutie:      16,
+			Frecventa:         "1800 MHz",
+			MemorieRAM:        "32 GB",
+			TipStocare:        "SSD",
+			CapacitateHDD:     "476 GB",
+			PlacaDeBaza:       "LENOVO 20YQ003WRI",
+			PlacaVideo:        "AMD Radeon(TM) Graphics",
+			ModelStocare:      "SAMSUNG MZVL2512HCJQ-00BL7",
+			SerialPlacaDeBaza: "L1HF16P03YT",
+		},
+		os: OSInfo{
+			Nume:           "Microsoft Windows [Version 10.0.22631.3737]",
+			Arhitectura:    runtime.GOARCH,
+			DataInstalarii: "2023-11-04 14:15:22",
+			Licenta:        "N/A",
+		},
+		programs: []ProgramInfo{
+			{Nume: "Lenovo Vantage Service", Versiune: "3.13.43.0", Producator: "Lenovo Group Ltd.", DataInstalare: "20231105", Sursa: "wmic"},
+			{Nume: "Microsoft Teams Meeting Add-in for Microsoft Office", Versiune: "1.24.2203", Producator: "Microsoft", DataInstalare: "20240220", Sursa: "wmic"},
+		},
+		security: "Windows Defender",
+	},
+	{
+		// Masina virtuala fara NVMe (iesire goala) si fara SecurityCenter2
+		corpus: "windows-server-vm-fara-nvme",
+		hardware: HardwareInfo{
+			Procesor:          "Intel(R) Xeon(R) Gold 6252 CPU @ 2.10GHz",
+			Nuclee:            4,
+			FireExecutie:      4,
+			Frecventa:         "2095 MHz",
+			MemorieRAM:        "16 GB",
+			TipStocare:        "N/A",
+			CapacitateHDD:     "N/A",
+			PlacaDeBaza:       "Microsoft Corporation Virtual Machine",
+			PlacaVideo:        "Microsoft Hyper-V Video",
+			SerialPlacaDeBaza: "0000-0011-5581-4373-0413-6072-38",
+		},
+		os: OSInfo{
+			Nume:           "Microsoft Windows [Version 10.0.20348.2527]",
+			Arhitectura:    runtime.GOARCH,
+			DataInstalarii: "2022-09-09 08:12:45",
+			Licenta:        "N/A",
+		},
+		programs: []ProgramInfo{
+			{Nume: "Microsoft SQL Server 2019 (64-bit)", Versiune: "15.0.2000.5", Producator: "Microsoft Corporation", DataInstalare: "20220910", Sursa: "wmic"},
+		},
+		security:    "N/A",
+		securityErr: true,
+	},
+}
+
+func TestWindowsCorpora(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range windowsCorpora {
+		t.Run(tc.corpus, func(t *testing.T) {
+			useWindowsCorpus(t, filepath.Join("testdata", "comenzi", tc.corpus))
+
+			hardware, err := GetHardwareInfo(ctx)
+			if err != nil {
+				t.Fatalf("GetHardwareInfo: %v", err)
+			}
+			if *hardware != tc.hardware {
+				t.Errorf("GetHardwareInfo:\n got  %+v\n want %+v", *hardware, tc.hardware)
+			}
+
+			osInfo, err := GetOSInfo(ctx)
+			if err != nil {
+				t.Fatalf("GetOSInfo: %v", err)
+			}
+			if *osInfo != tc.os {
+				t.Errorf("GetOSInfo:\n got  %+v\n want %+v", *osInfo, tc.os)
+			}
+
+			programs, err := GetInstalledPrograms(ctx)
+			if err != nil {
+				t.Fatalf("GetInstalledPrograms: %v", err)
+			}
+			if !reflect.DeepEqual(programs, tc.programs) {
+				t.Errorf("GetInstalledPrograms:\n got  %+v\n want %+v", programs, tc.programs)
+			}
+
+			security, err := GetSecurityInfo(ctx)
+			if (err != nil) != tc.securityErr {
+				t.Errorf("GetSecurityInfo: eroare %v, asteptata %v", err, tc.securityErr)
+			}
+			if security != tc.security {
+				t.Errorf("GetSecurityInfo = %q, asteptat %q", security, tc.security)
+			}
+		})
+	}
+}
+
+func TestMediaTypeName(t *testing.T) {
+	tests := []struct {
+		mediaType interface{}
+		want      string
+	}{
+		{float64(3), "HDD"},
+		{float64(4), "SSD"},
+		{float64(5), "SCM"},
+		{float64(0), "Unspecified"},
+		{"SSD", "SSD"},
+		{nil, "N/A"},
+	}
+	for _, tc := range tests {
+		if got := mediaTypeName(tc.mediaType); got != tc.want {
+			t.Errorf("mediaTypeName(%v) = %q, asteptat %q", tc.mediaType, got, tc.want)
+		}
+	}
+}
+
+// Cand wmic afiseaza mai multe placi de baza, se pastreaza doar prima
+func TestWindowsFirstBaseboard(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join("testdata", "comenzi", "windows10-desktop-nvme")
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(source, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, entry.Name()), data, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	baseboard := commandFileName("cmd", []string{"/c", "wmic baseboard get Manufacturer,Product,SerialNumber /FORMAT:LIST"}) + ".out"
+	output := "\r\r\n\r\r\nManufacturer=ASUSTeK COMPUTER INC.\r\r\nProduct=PRIME B365M-A\r\r\nSerialNumber=Default string\r\r\n" +
+		"\r\r\n\r\r\nManufacturer=Other\r\r\nProduct=Second Board\r\r\nSerialNumber=SB-0001\r\r\n\r\r\n"
+	err = os.WriteFile(filepath.Join(dir, baseboard), []byte(output), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useWindowsCorpus(t, dir)
+	hardware, err := GetHardwareInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetHardwareInfo: %v", err)
+	}
+	if hardware.PlacaDeBaza != "ASUSTeK COMPUTER INC. PRIME B365M-A" {
+		t.Errorf("PlacaDeBaza = %q", hardware.PlacaDeBaza)
+	}
+	// Seria generica a primei placi nu este inlocuita cu seria celei de-a doua
+	if hardware.SerialPlacaDeBaza != "N/A" {
+		t.Errorf("SerialPlacaDeBaza = %q", hardware.SerialPlacaDeBaza)
+	}
+}
+
+func TestBoardSerial(t *testing.T) {
+	tests := []struct {
+		serial, want string
+	}{
+		{" 190949528800914 ", "190949528800914"},
+		{"Default string", "N/A"},
+		{"To Be Filled By O.E.M.", "N/A"},
+		{"None", "N/A"},
+		{"", "N/A"},
+	}
+	for _, tc := range tests {
+		if got := boardSerial(tc.serial); got != tc.want {
+			t.Errorf("boardSerial(%q) = %q, asteptat %q", tc.serial, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package colector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Interfata prin care colectoarele executa comenzi externe (wmic, PowerShell, rpm...)
+// Permite inlocuirea executiei reale cu o inregistrare, pentru a testa
+// parsarea rezultatelor Windows pe Linux
+type CommandRunner interface {
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// Executorul folosit de colectoare
+var Runner CommandRunner = ExecRunner{}
+
+// Sistemul de operare pentru care se colecteaza informatiile
+// Este diferit de runtime.GOOS doar la rularea unui corpus inregistrat pe alt sistem
+var TargetOS = runtime.GOOS
+
+// Executor care ruleaza comenzile pe sistemul curent
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// Executor care ruleaza comenzile prin alt executor si salveaza rezultatele
+// in directorul dat, pentru a fi redate ulterior cu ReplayRunner
+type RecordingRunner struct {
+	Runner CommandRunner
+	Dir    string
+}
+
+func (r RecordingRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := r.Runner.Run(ctx, name, args...)
+
+	path := filepath.Join(r.Dir, commandFileName(name, args))
+	var writeErr error
+	if err != nil {
+		writeErr = os.WriteFile(path+".err", []byte(err.Error()), 0644)
+	} else {
+		writeErr = os.WriteFile(path+".out", out, 0644)
+	}
+	if writeErr != nil {
+		fmt.Printf("Eroare la înregistrarea comenzii '%s': %v\n", commandLine(name, args), writeErr)
+	}
+
+	return out, err
+}
+
+// Executor care reda rezultatele salvate de RecordingRunner, fara a rula nimic
+type ReplayRunner struct {
+	Dir string
+}
+
+func (r ReplayRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	path := filepath.Join(r.Dir, commandFileName(name, args))
+
+	out, err := os.ReadFile(path + ".out")
+	if err == nil {
+		return out, nil
+	}
+
+	message, err := os.ReadFile(path + ".err")
+	if err == nil {
+		return nil, errors.New(string(message))
+	}
+
+	return nil, fmt.Errorf("comanda '%s' nu există în corpusul %s", commandLine(name, args), r.Dir)
+}
+
+// Functie care intoarce linia de comanda completa, ca text
+func commandLine(name string, args []string) string {
+	return strings.TrimSpace(name + " " + strings.Join(args, " "))
+}
+
+// Functie care intoarce numele fisierului din corpus pentru o comanda:
+// linia de comanda cu caracterele speciale inlocuite, urmata de un hash scurt
+func commandFileName(name string, args []string) string {
+	line := commandLine(name, args)
+	sum := sha256.Sum256([]byte(line))
+
+	readable := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, line)
+	if len(readable) > 60 {
+		readable = readable[:60]
+	}
+
+	return readable + "-" + hex.EncodeToString(sum[:4])
+}
@@ -0,0 +1,97 @@
+package colector
+
+import (
+	"time"
+)
+
+// Tipurile de date trimise la server: datele live sunt trimise des,
+// inventarul (sistem de operare, hardware, software, securitate) rar
+const (
+	TipLive     = "live"
+	TipInventar = "inventar"
+)
+
+// Functie care intoarce tipul de date produs de un colector
+func PayloadType(collector Collector) string {
+	if collector.Name() == SectiuneLive {
+		return TipLive
+	}
+	return TipInventar
+}
+
+// Planificatorul colectoarelor: fiecare colector ruleaza la propriul interval
+type Scheduler struct {
+	collectors []Collector
+	next       map[string]time.Time
+}
+
+// Functie pentru a crea un planificator; toate colectoarele ruleaza la prima verificare
+func NewScheduler(collectors []Collector) *Scheduler {
+	return &Scheduler{
+		collectors: append([]Collector(nil), collectors...),
+		next:       make(map[string]time.Time),
+	}
+}
+
+// Functie care intoarce colectoarele care trebuie rulate acum, grupate dupa
+// tipul de date, si programeaza urmatoarea lor rulare
+func (s *Scheduler) Due(now time.Time) map[string][]Collector {
+	due := make(map[string][]Collector)
+	for _, collector := range s.collectors {
+		if now.Before(s.next[collector.Name()]) {
+			continue
+		}
+		tip := PayloadType(collector)
+		due[tip] = append(due[tip], collector)
+		s.next[collector.Name()] = now.Add(collector.Interval())
+	}
+	return due
+}
+
+// Functie care cere rularea colectoarelor de tipul dat la urmatoarea verificare,
+// de exemplu cand statia s-a schimbat sau cand trimiterea datelor a esuat
+func (s *Scheduler) Trigger(tip string) {
+	for _, collector := range s.collectors {
+		if PayloadType(collector) == tip {
+			delete(s.next, collector.Name())
+		}
+	}
+}
+
+// Functie care intoarce momentul urmatoarei rulari programate
+func (s *Scheduler) NextRun() time.Time {
+	var next time.Time
+	for _, collector := range s.collectors {
+		at := s.next[collector.Name()]
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	return next
+}
+
+// Functie care construieste plicul cu datele de tipul dat pentru server
+// Sectiunile al caror colector a esuat lipsesc din plic
+// Plicul poarta momentul colectarii, pentru a putea fi trimis si mai tarziu
+func Payload(tip string, results []Result) *Envelope {
+	envelope := NewEnvelope(tip, CollectedAt(results))
+	for _, result := range results {
+		if result.Err != nil || result.Data == nil {
+			continue
+		}
+		envelope.Sectiuni.Set(result.Data)
+	}
+	return envelope
+}
+
+// Functie care intoarce momentul colectarii unui set de rezultate: cel mai recent
+// moment la care a terminat un colector
+func CollectedAt(results []Result) time.Time {
+	var collectedAt time.Time
+	for _, result := range results {
+		if result.CollectedAt.After(collectedAt) {
+			collectedAt = result.CollectedAt
+		}
+	}
+	return collectedAt
+}
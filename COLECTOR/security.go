@@ -0,0 +1,32 @@
+package colector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Informatii despre securitate (numele antivirusului)
+type SecurityInfo string
+
+// Functie pentru a obtine informatii despre securitate
+func GetSecurityInfo(ctx context.Context) (string, error) {
+	// Implementati metoda pentru a obtine informatii despre statusul de securitate al sistemului
+	// Informatiile pot include statusul antivirusului, firewall-ului, etc.
+
+	// Exemplu simplificat pentru Windows
+	out, err := Runner.Run(ctx, "cmd", "/c", "wmic /namespace:\\\\root\\SecurityCenter2 path AntiVirusProduct get displayName /FORMAT:LIST")
+	if err != nil {
+		return "N/A", fmt.Errorf("eroare la executarea comenzii 'wmic /namespace:\\\\root\\SecurityCenter2 path AntiVirusProduct get displayName': %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for _, line := range lines {
+		if strings.Contains(line, "=") {
+			fields := strings.Split(line, "=")
+			if len(fields) > 1 {
+				return strings.TrimSpace(fields[1]), nil
+			}
+		}
+	}
+	return "N/A", nil
+}
@@ -0,0 +1,74 @@
+package colector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Structura pentru informatii despre software (programe instalate)
+type SoftwareInfo struct {
+	ProgrameInstalate []ProgramInfo `json:"programe_instalate"`
+}
+
+// Structura pentru informatii despre un program instalat
+type ProgramInfo struct {
+	Nume          string `json:"nume"`
+	Versiune      string `json:"versiune"`
+	Producator    string `json:"producator"`
+	DataInstalare string `json:"data_instalare"`
+	Licenta       string `json:"licenta"`
+	Sursa         string `json:"sursa"` // wmic, dpkg, rpm, snap sau flatpak
+	// Alte informatii despre program
+}
+
+// Functie pentru a obtine informatii despre programele instalate
+func GetInstalledPrograms(ctx context.Context) ([]ProgramInfo, error) {
+	// Pe Linux se citesc bazele de date ale managerilor de pachete
+	if TargetOS == "linux" {
+		return getLinuxInstalledPrograms(ctx, SysRoot)
+	}
+
+	var programs []ProgramInfo
+
+	// Implementati metoda pentru a obtine lista de programe instalate pe sistemul de operare
+	// Iata un exemplu simplificat pentru Windows, care utilizeaza registrele de sistem
+	// pentru a obtine informatiile necesare. Trebuie adaptata pentru diferite sisteme de operare.
+
+	// Exemplu: Windows
+	out, err := Runner.Run(ctx, "cmd", "/c", "wmic product get Name,Version,Vendor,InstallDate /FORMAT:LIST")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'wmic product get ...': %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	var program ProgramInfo
+	for _, line := range lines {
+		if strings.Contains(line, "=") {
+			fields := strings.Split(line, "=")
+			switch strings.TrimSpace(fields[0]) {
+			case "Name":
+				program.Nume = strings.TrimSpace(fields[1])
+			case "Version":
+				program.Versiune = strings.TrimSpace(fields[1])
+			case "Vendor":
+				program.Producator = strings.TrimSpace(fields[1])
+			case "InstallDate":
+				program.DataInstalare = strings.TrimSpace(fields[1])
+			}
+		} else {
+			// Finalizam informatiile pentru un program si adaugam in lista
+			if program.Nume != "" {
+				program.Sursa = "wmic"
+				programs = append(programs, program)
+				program = ProgramInfo{}
+			}
+		}
+	}
+	// Adaugam ultimul program (daca exista)
+	if program.Nume != "" {
+		program.Sursa = "wmic"
+		programs = append(programs, program)
+	}
+
+	return programs, nil
+}
@@ -0,0 +1,33 @@
+package colector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Structura pentru informatii despre utilizator
+type UserInfo struct {
+	NumeUtilizator string `json:"nume_utilizator"`
+	GrupUtilizator string `json:"grup_utilizator"`
+	// Alte informatii despre utilizator
+}
+
+// Functie pentru a obtine informatii despre utilizatorul curent
+func GetCurrentUserInfo(ctx context.Context) (*UserInfo, error) {
+	userInfo := &UserInfo{}
+
+	// Implementati metoda pentru a obtine informatii despre utilizatorul curent
+	// Aceasta poate include numele utilizatorului, grupul utilizatorului, etc.
+
+	// Exemplu simplificat pentru Windows
+	out, err := Runner.Run(ctx, "cmd", "/c", "whoami")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la executarea comenzii 'whoami': %w", err)
+	}
+	userInfo.NumeUtilizator = strings.TrimSpace(string(out))
+
+	// Pentru grupul utilizatorului, se poate face o interogare suplimentara sau folosind pachete/librarii aditionale
+
+	return userInfo, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"colector"
+)
+
+func main() {
+	flag.StringVar(&colector.SysRoot, "sysroot", colector.SysRoot, "rădăcina sistemului de fișiere din care se citesc informațiile pe Linux")
+	recordDir := flag.String("record", "", "directorul în care se salvează rezultatele comenzilor externe")
+	replayDir := flag.String("replay", "", "directorul din care se redau rezultatele comenzilor externe, fără a le rula")
+	flag.StringVar(&colector.TargetOS, "os", colector.TargetOS, "sistemul de operare pentru care se interpretează rezultatele (windows, linux, darwin)")
+	flag.Parse()
+
+	// Comenzile externe pot fi înregistrate sau redate dintr-un corpus
+	switch {
+	case *replayDir != "":
+		colector.Runner = colector.ReplayRunner{Dir: *replayDir}
+	case *recordDir != "":
+		err := os.MkdirAll(*recordDir, 0755)
+		if err != nil {
+			fmt.Printf("Eroare la crearea directorului de înregistrare: %v\n", err)
+			return
+		}
+		colector.Runner = colector.RecordingRunner{Runner: colector.Runner, Dir: *recordDir}
+	}
+
+	ctx := context.Background()
+
+	// Creează fișierul JSON dacă nu există
+	fileName := "live_data.json"
+	if _, err := os.Stat(fileName); os.IsNotExist(err) {
+		_, err = os.Create(fileName)
+		if err != nil {
+			fmt.Printf("Eroare la crearea fișierului JSON: %v\n", err)
+			return
+		}
+	}
+
+	// Colectoarele folosite de extractor
+	registry := colector.NewStandardRegistry()
+
+	for {
+		// Obținere informații despre sistemul de operare, hardware, software etc.
+		results := colector.Collect(ctx, registry.Collectors())
+		for _, result := range results {
+			if result.Err != nil {
+				fmt.Printf("Eroare la obținerea informațiilor: %v\n", result.Err)
+				continue
+			}
+			if liveInfo, ok := result.Data.(*colector.LiveSystemInfo); ok {
+				fmt.Printf("--------------------\n")
+				fmt.Printf("Informații Live:\n")
+				fmt.Printf("Utilizare CPU: %.2f%%\n", liveInfo.UtilizareCPU)
+				fmt.Printf("Utilizare RAM: %.2f%%\n", liveInfo.UtilizareRAM)
+				fmt.Printf("Trafic Retea Trimis: %d\n", liveInfo.TraficTrimis)
+				fmt.Printf("Trafic Retea Receptionat: %d\n", liveInfo.TraficReceptionat)
+			}
+		}
+
+		// Creare structura pentru informațiile complete despre sistem
+		systemInfo := colector.SystemInfo(results)
+
+		// Serializează toate datele în format JSON
+		jsonData, err := json.MarshalIndent(systemInfo, "", "  ")
+		if err != nil {
+			fmt.Printf("Eroare la serializarea JSON: %v\n", err)
+			continue // Trece la următoarea iterație a buclei
+		}
+
+		// Scrie datele JSON în fișier
+		err = os.WriteFile(fileName, jsonData, 0644)
+		if err != nil {
+			fmt.Printf("Eroare la scrierea în fișierul JSON: %v\n", err)
+			continue // Trece la următoarea iterație a buclei
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+}
@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// API-ul de interogare: citește stațiile, metadatele și programele instalate
+// din tabelele existente și întoarce JSON
+//
+//	GET /stations                 stațiile, cu filtrare după nume și persoană
+//	GET /stations/{id}            o stație, cu metadatele din 'metadate_statii'
+//	GET /stations/{id}/software   programele instalate pe o stație
+//	GET /software?name=           stațiile pe care este instalat un program
+//	GET /stations/{id}/software/events   istoricul software al unei stații
+//	GET /software/events?name=           istoricul software al unui program
+//	GET /stations/{id}/metadata/changes  modificările metadatelor unei stații
+//	GET /metadata/changes?suspect=true   modificările suspecte ale tuturor stațiilor
+//
+// # Metricile sunt interogate prin rutele din metrics.go
+//
+// Listele acceptă limit (implicit 50, maxim 500), offset și sort; sort primește
+// numele unei coloane, cu prefixul '-' pentru ordine descrescătoare
+// Listele de programe conțin implicit doar programele instalate; status=dezinstalat
+// sau status=toate includ și programele care au dispărut din inventar
+//
+// Dacă http.token_api este setat, cererile trebuie să conțină antetul
+// "Authorization: Bearer <token>"; altfel primesc 401
+type queryAPI struct {
+	db        *sql.DB
+	retention metricsRetention // pentru alegerea tabelului din care se citesc metricile
+	token     string           // gol: API-ul nu cere autentificare
+}
+
+// Paginarea implicită și maximă a listelor
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Funcție pentru a crea API-ul de interogare
+func newQueryAPI(db *sql.DB, retention metricsRetention, token string) *queryAPI {
+	return &queryAPI{db: db, retention: retention, token: token}
+}
+
+// Funcție care înregistrează rutele API-ului
+func (api *queryAPI) register(mux *http.ServeMux) {
+	routes := []struct {
+		pattern string
+		handler http.HandlerFunc
+	}{
+		{"GET /stations", api.listStations},
+		{"GET /stations/{id}", api.getStation},
+		{"GET /stations/{id}/software", api.listStationSoftware},
+		{"GET /software", api.findSoftware},
+		{"GET /stations/{id}/software/events", api.listStationSoftwareEvents},
+		{"GET /software/events", api.listSoftwareEvents},
+		{"GET /stations/{id}/metadata/changes", api.listStationMetadataChanges},
+		{"GET /metadata/changes", api.listMetadataChanges},
+		{"GET /stations/{id}/metrics", api.stationMetrics},
+		{"GET /metrics/top", api.topStations},
+	}
+	for _, route := range routes {
+		mux.HandleFunc(route.pattern, api.authorized(route.handler))
+	}
+}
+
+// Funcție care acceptă cererea doar dacă are tokenul API-ului
+func (api *queryAPI) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorizedRequest(r, api.token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="inventar"`)
+			writeError(w, http.StatusUnauthorized, "Cerere neautorizată")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// O pagină de rezultate
+type page struct {
+	Total     int         `json:"total"`
+	Limita    int         `json:"limita"`
+	Deplasare int         `json:"deplasare"`
+	Elemente  interface{} `json:"elemente"`
+}
+
+// Parametrii unei liste: filtrele, ordinea și paginarea
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+	orderBy    string
+	limit      int
+	offset     int
+}
+
+// Funcție care adaugă o condiție; '?' este înlocuit cu următorul parametru
+func (q *listQuery) where(condition string, arg interface{}) {
+	q.args = append(q.args, arg)
+	q.conditions = append(q.conditions, strings.Replace(condition, "?", "$"+strconv.Itoa(len(q.args)), 1))
+}
+
+// Caracterele speciale ale tiparelor LIKE, precedate de caracterul de escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Funcție care adaugă condiția ca o coloană să conțină textul dat, fără a ține
+// cont de majuscule; '%' și '_' din text sunt căutate ca atare
+func (q *listQuery) contains(column string, text string) {
+	q.where(column+` ILIKE '%' || ? || '%' ESCAPE '\'`, likeEscaper.Replace(text))
+}
+
+// Funcție care întoarce clauza WHERE
+func (q *listQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// Funcție care întoarce clauzele ORDER BY, LIMIT și OFFSET
+func (q *listQuery) pageClause() string {
+	return fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", q.orderBy, q.limit, q.offset)
+}
+
+// Funcție care citește paginarea și ordinea din cerere
+// sortColumns asociază valorile acceptate pentru sort coloanelor SQL; prima
+// coloană din tiebreak asigură o ordine stabilă între pagini
+func parseListQuery(r *http.Request, sortColumns map[string]string, defaultSort, tiebreak string) (*listQuery, error) {
+	invalid := &validationError{}
+	values := r.URL.Query()
+	q := &listQuery{limit: defaultPageSize}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			invalid.add("limit", "trebuie să fie un număr între 1 și %d", maxPageSize)
+		}
+		q.limit = limit
+	}
+	if value := values.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			invalid.add("offset", "trebuie să fie un număr pozitiv")
+		}
+		q.offset = offset
+	}
+
+	sortKey := values.Get("sort")
+	if sortKey == "" {
+		sortKey = defaultSort
+	}
+	direction := "ASC"
+	if strings.HasPrefix(sortKey, "-") {
+		direction = "DESC"
+		sortKey = sortKey[1:]
+	}
+	column, ok := sortColumns[sortKey]
+	if !ok {
+		names := make([]string, 0, len(sortColumns))
+		for name := range sortColumns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		invalid.add("sort", "coloana '%s' nu este cunoscută (acceptate: %s)", sortKey, strings.Join(names, ", "))
+	}
+	q.orderBy = fmt.Sprintf("%s %s NULLS LAST, %s", column, direction, tiebreak)
+
+	if len(invalid.erori) > 0 {
+		return nil, invalid
+	}
+	return q, nil
+}
+
+// Funcție care adaugă filtrul după starea programelor (instalat, dezinstalat, toate)
+// column este coloana 'dezinstalat_la' a tabelului interogat
+func (q *listQuery) softwareStatus(r *http.Request, column string) error {
+	switch status := r.URL.Query().Get("status"); status {
+	case "", "instalat":
+		q.conditions = append(q.conditions, column+" IS NULL")
+	case "dezinstalat":
+		q.conditions = append(q.conditions, column+" IS NOT NULL")
+	case "toate":
+	default:
+		invalid := &validationError{}
+		invalid.add("status", "starea '%s' nu este cunoscută (acceptate: instalat, dezinstalat, toate)", status)
+		return invalid
+	}
+	return nil
+}
+
+// Funcție care citește ID-ul stației din cale
+func stationIDFromPath(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		invalid := &validationError{}
+		invalid.add("id", "ID-ul stației trebuie să fie un număr pozitiv")
+		return 0, invalid
+	}
+	return id, nil
+}
+
+// Funcție care trimite un răspuns JSON
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// Funcție care trimite o eroare JSON, fără detalii pe câmpuri
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Eroare: message})
+}
+
+// Funcție care trimite o eroare internă și o afișează în jurnal
+func writeInternalError(w http.ResponseWriter, err error) {
+	fmt.Printf("Eroare la interogarea bazei de date: %v\n", err)
+	writeError(w, http.StatusInternalServerError, "Eroare la interogarea bazei de date")
+}
+
+// Funcție care rulează interogarea de numărare și interogarea paginată
+// scan citește un rând și îl adaugă la rezultate
+func queryPage(ctx context.Context, db *sql.DB, from string, columns string, q *listQuery, scan func(*sql.Rows) error) (int, error) {
+	var total int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+from+q.whereClause(), q.args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("eroare la numărarea rezultatelor: %w", err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT "+columns+" FROM "+from+q.whereClause()+q.pageClause(), q.args...)
+	if err != nil {
+		return 0, fmt.Errorf("eroare la citirea rezultatelor: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = scan(rows)
+		if err != nil {
+			return 0, fmt.Errorf("eroare la citirea rezultatelor: %w", err)
+		}
+	}
+	return total, rows.Err()
+}
+
+// Structura pentru o stație din listă
+type stationSummary struct {
+	ID             int     `json:"id_statie"`
+	Nume           string  `json:"nume_statie"`
+	Identificator  *string `json:"identificator_statie"`
+	IDPersoana     int     `json:"id_persoana"`
+	NumePersoana   string  `json:"nume_persoana"`
+	DecalajCeasMs  *int64  `json:"decalaj_ceas_ms"`
+	UltimaSecventa *int64  `json:"ultima_secventa"`
+}
+
+const stationColumns = `s.id_statie, s.nume_statie, s.identificator_statie, s.id_persoana, p.nume,
+	s.decalaj_ceas_ms, s.ultima_secventa`
+
+const stationFrom = `statii_de_lucru s JOIN persoane p ON p.id_persoana = s.id_persoana`
+
+// Funcție care citește o stație dintr-un rând
+func scanStation(row interface{ Scan(...interface{}) error }, s *stationSummary) error {
+	return row.Scan(&s.ID, &s.Nume, &s.Identificator, &s.IDPersoana, &s.NumePersoana, &s.DecalajCeasMs, &s.UltimaSecventa)
+}
+
+// GET /stations?name=&person=
+func (api *queryAPI) listStations(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, map[string]string{
+		"id":       "s.id_statie",
+		"nume":     "s.nume_statie",
+		"persoana": "p.nume",
+	}, "nume", "s.id_statie")
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	if name := r.URL.Query().Get("name"); name != "" {
+		q.contains("s.nume_statie", name)
+	}
+	if person := r.URL.Query().Get("person"); person != "" {
+		q.contains("p.nume", person)
+	}
+
+	stations := []stationSummary{}
+	total, err := queryPage(r.Context(), api.db, stationFrom, stationColumns, q, func(rows *sql.Rows) error {
+		var s stationSummary
+		err := scanStation(rows, &s)
+		if err != nil {
+			return err
+		}
+		stations = append(stations, s)
+		return nil
+	})
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: stations})
+}
+
+// Metadatele unei stații, din 'metadate_statii'
+type stationMetadata struct {
+	ProducatorProcesor         *string `json:"producator_procesor"`
+	ModelProcesor              *string `json:"model_procesor"`
+	Nuclee                     *int    `json:"nuclee"`
+	FireExecutie               *int    `json:"fire_executie"`
+	Frecventa                  *string `json:"frecventa"`
+	MemorieRAM                 *string `json:"memorie_ram"`
+	TipStocare                 *string `json:"tip_stocare"`
+	CapacitateStocare          *string `json:"capacitate_stocare"`
+	PlacaDeBaza                *string `json:"placa_de_baza"`
+	SerialPlacaDeBaza          *string `json:"serial_placa_de_baza"`
+	PlacaVideo                 *string `json:"placa_video"`
+	ModelStocare               *string `json:"model_stocare"`
+	BIOS                       *string `json:"bios"`
+	SistemOperare              *string `json:"sistem_operare"`
+	VersiuneSoftware           *string `json:"versiune_software"`
+	ArhitecturaSistemOperare   *string `json:"arhitectura_sistem_operare"`
+	DataInstalareSistemOperare *string `json:"data_instalare_sistem_operare"`
+	LicentaSistemOperare       *string `json:"licenta_sistem_operare"`
+	VersiuneKernel             *string `json:"versiune_kernel"`
+	Securitate                 *string `json:"securitate"`
+}
+
+// Structura pentru detaliile unei stații
+type stationDetail struct {
+	stationSummary
+	NumarPrograme int              `json:"numar_programe"`
+	Metadate      *stationMetadata `json:"metadate"`
+}
+
+// GET /stations/{id}
+func (api *queryAPI) getStation(w http.ResponseWriter, r *http.Request) {
+	id, err := stationIDFromPath(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	var station stationDetail
+	row := api.db.QueryRowContext(r.Context(), "SELECT "+stationColumns+" FROM "+stationFrom+" WHERE s.id_statie = $1", id)
+	err = scanStation(row, &station.stationSummary)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Stația %d nu există", id))
+		return
+	}
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	err = api.db.QueryRowContext(r.Context(), "SELECT COUNT(*) FROM software_instalat WHERE id_statie = $1 AND dezinstalat_la IS NULL", id).Scan(&station.NumarPrograme)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	// Stațiile care nu au trimis încă inventarul nu au metadate
+	m := &stationMetadata{}
+	err = api.db.QueryRowContext(r.Context(), `
+		SELECT producator_procesor, model_procesor, nuclee, fire_executie, frecventa, memorie_ram,
+			tip_stocare, capacitate_stocare, placa_de_baza, serial_placa_de_baza, placa_video, model_stocare, bios,
+			sistem_operare, versiune_software, arhitectura_sistem_operare, data_instalare_sistem_operare,
+			licenta_sistem_operare, versiune_kernel, securitate
+		FROM metadate_statii WHERE id_statie = $1
+	`, id).Scan(&m.ProducatorProcesor, &m.ModelProcesor, &m.Nuclee, &m.FireExecutie, &m.Frecventa, &m.MemorieRAM,
+		&m.TipStocare, &m.CapacitateStocare, &m.PlacaDeBaza, &m.SerialPlacaDeBaza, &m.PlacaVideo, &m.ModelStocare, &m.BIOS,
+		&m.SistemOperare, &m.VersiuneSoftware, &m.ArhitecturaSistemOperare, &m.DataInstalareSistemOperare,
+		&m.LicentaSistemOperare, &m.VersiuneKernel, &m.Securitate)
+	switch {
+	case err == nil:
+		station.Metadate = m
+	case !errors.Is(err, sql.ErrNoRows):
+		writeInternalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, station)
+}
+
+// Structura pentru un program instalat
+type installedProgram struct {
+	Nume          string     `json:"nume"`
+	Versiune      string     `json:"versiune"`
+	Producator    *string    `json:"producator"`
+	DataInstalare *string    `json:"data_instalare"`
+	Licenta       *string    `json:"licenta"`
+	Sursa         *string    `json:"sursa"`
+	DezinstalatLa *time.Time `json:"dezinstalat_la"`
+}
+
+// GET /stations/{id}/software?name=&vendor=&source=&status=
+func (api *queryAPI) listStationSoftware(w http.ResponseWriter, r *http.Request) {
+	id, err := stationIDFromPath(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	q, err := parseListQuery(r, map[string]string{
+		"nume":           "nume",
+		"versiune":       "versiune",
+		"producator":     "producator",
+		"data_instalare": "data_instalare",
+		"sursa":          "sursa",
+		"dezinstalat_la": "dezinstalat_la",
+	}, "nume", "nume, versiune")
+	if err == nil {
+		err = q.softwareStatus(r, "dezinstalat_la")
+	}
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	var exists bool
+	err = api.db.QueryRowContext(r.Context(), "SELECT EXISTS (SELECT 1 FROM statii_de_lucru WHERE id_statie = $1)", id).Scan(&exists)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Stația %d nu există", id))
+		return
+	}
+
+	q.where("id_statie = ?", id)
+	if name := r.URL.Query().Get("name"); name != "" {
+		q.contains("nume", name)
+	}
+	if vendor := r.URL.Query().Get("vendor"); vendor != "" {
+		q.contains("producator", vendor)
+	}
+	if source := r.URL.Query().Get("source"); source != "" {
+		q.where("sursa = ?", source)
+	}
+
+	programs := []installedProgram{}
+	total, err := queryPage(r.Context(), api.db, "software_instalat", "nume, versiune, producator, data_instalare, licenta, sursa, dezinstalat_la", q, func(rows *sql.Rows) error {
+		var p installedProgram
+		err := rows.Scan(&p.Nume, &p.Versiune, &p.Producator, &p.DataInstalare, &p.Licenta, &p.Sursa, &p.DezinstalatLa)
+		if err != nil {
+			return err
+		}
+		programs = append(programs, p)
+		return nil
+	})
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: programs})
+}
+
+// Structura pentru o instalare a unui program pe o stație
+type programInstallation struct {
+	IDStatie      int        `json:"id_statie"`
+	NumeStatie    string     `json:"nume_statie"`
+	Nume          string     `json:"nume"`
+	Versiune      string     `json:"versiune"`
+	Producator    *string    `json:"producator"`
+	DataInstalare *string    `json:"data_instalare"`
+	DezinstalatLa *time.Time `json:"dezinstalat_la"`
+}
+
+// GET /software?name=&version=&status=
+// name caută după o parte din numele programului; version cere versiunea exactă
+func (api *queryAPI) findSoftware(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, map[string]string{
+		"statie":   "s.nume_statie",
+		"nume":     "si.nume",
+		"versiune": "si.versiune",
+	}, "nume", "si.id_software")
+	if err == nil {
+		err = q.softwareStatus(r, "si.dezinstalat_la")
+	}
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		invalid := &validationError{}
+		invalid.add("name", "lipsește")
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", invalid)
+		return
+	}
+	q.contains("si.nume", name)
+	if version := r.URL.Query().Get("version"); version != "" {
+		q.where("si.versiune = ?", version)
+	}
+
+	installations := []programInstallation{}
+	from := "software_instalat si JOIN statii_de_lucru s ON s.id_statie = si.id_statie"
+	total, err := queryPage(r.Context(), api.db, from, "si.id_statie, s.nume_statie, si.nume, si.versiune, si.producator, si.data_instalare, si.dezinstalat_la", q, func(rows *sql.Rows) error {
+		var p programInstallation
+		err := rows.Scan(&p.IDStatie, &p.NumeStatie, &p.Nume, &p.Versiune, &p.Producator, &p.DataInstalare, &p.DezinstalatLa)
+		if err != nil {
+			return err
+		}
+		installations = append(installations, p)
+		return nil
+	})
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: installations})
+}
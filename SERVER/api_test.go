@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// '%' și '_' din textul căutat nu trebuie să fie interpretate ca tipare
+func TestListQueryContains(t *testing.T) {
+	q := &listQuery{}
+	q.where("s.id_statie = ?", 7)
+	q.contains("s.nume_statie", `50%_off\`)
+
+	wantConditions := []string{"s.id_statie = $1", `s.nume_statie ILIKE '%' || $2 || '%' ESCAPE '\'`}
+	if !reflect.DeepEqual(q.conditions, wantConditions) {
+		t.Errorf("conditions = %q, așteptat %q", q.conditions, wantConditions)
+	}
+	wantArgs := []interface{}{7, `50\%\_off\\`}
+	if !reflect.DeepEqual(q.args, wantArgs) {
+		t.Errorf("args = %q, așteptat %q", q.args, wantArgs)
+	}
+}
+
+// Doar coloanele din lista albă pot fi folosite pentru sort, iar paginarea
+// este limitată la valorile acceptate
+func TestParseListQuery(t *testing.T) {
+	sortColumns := map[string]string{
+		"moment": "m.moment",
+		"camp":   "m.camp",
+	}
+	tests := []struct {
+		query       string
+		wantFields  []string
+		wantOrderBy string
+		wantLimit   int
+		wantOffset  int
+	}{
+		{query: "", wantOrderBy: "m.moment DESC NULLS LAST, m.id_modificare", wantLimit: defaultPageSize},
+		{query: "sort=camp&limit=500&offset=1000", wantOrderBy: "m.camp ASC NULLS LAST, m.id_modificare", wantLimit: maxPageSize, wantOffset: 1000},
+		{query: "sort=-camp&limit=1", wantOrderBy: "m.camp DESC NULLS LAST, m.id_modificare", wantLimit: 1},
+		{query: "sort=m.id_statie", wantFields: []string{"sort"}},
+		{query: "sort=camp%3BDROP%20TABLE%20persoane", wantFields: []string{"sort"}},
+		{query: "sort=--camp", wantFields: []string{"sort"}},
+		{query: "limit=0", wantFields: []string{"limit"}},
+		{query: "limit=501", wantFields: []string{"limit"}},
+		{query: "limit=zece", wantFields: []string{"limit"}},
+		{query: "offset=-1", wantFields: []string{"offset"}},
+		{query: "limit=-5&offset=x&sort=nume", wantFields: []string{"limit", "offset", "sort"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/metadata/changes?"+tt.query, nil)
+			q, err := parseListQuery(r, sortColumns, "-moment", "m.id_modificare")
+			if tt.wantFields != nil {
+				if err == nil {
+					t.Fatalf("eroare așteptată, ordine %q", q.orderBy)
+				}
+				if fields := invalidFields(t, err); !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("câmpuri invalide %q, așteptat %q", fields, tt.wantFields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseListQuery: %v", err)
+			}
+			if q.orderBy != tt.wantOrderBy || q.limit != tt.wantLimit || q.offset != tt.wantOffset {
+				t.Errorf("ordine %q, limită %d, deplasare %d; așteptat %q, %d, %d",
+					q.orderBy, q.limit, q.offset, tt.wantOrderBy, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// Rutele API-ului cer tokenul, dacă este configurat; cererile neautorizate nu
+// ajung la baza de date
+func TestQueryAPIAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "fără token configurat", want: http.StatusOK},
+		{name: "token corect", token: "secret", header: "Bearer secret", want: http.StatusOK},
+		{name: "fără antet", token: "secret", want: http.StatusUnauthorized},
+		{name: "token greșit", token: "secret", header: "Bearer altul", want: http.StatusUnauthorized},
+		{name: "altă schemă", token: "secret", header: "Basic secret", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newQueryAPI(nil, metricsRetention{}, tt.token)
+			handler := api.authorized(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusOK, page{})
+			})
+			r := httptest.NewRequest("GET", "/stations", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, r)
+			if rec.Code != tt.want {
+				t.Errorf("cod = %d, așteptat %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	// Toate rutele înregistrate sunt protejate
+	mux := http.NewServeMux()
+	newQueryAPI(nil, metricsRetention{}, "secret").register(mux)
+	for _, path := range []string{"/stations", "/stations/1", "/software?name=x", "/metadata/changes", "/metrics/top"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("GET %s: cod = %d, așteptat %d", path, rec.Code, http.StatusUnauthorized)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Arhiva datelor brute primite de la agenți, folosită pentru audit și pentru
+// retrimiterea exactă a ceea ce a trimis un agent
+//
+// Datele sunt păstrate pe zile și pe stații, în <director>/<AAAA-LL-ZZ>/<statie>.gz
+// Fiecare cerere este un membru gzip separat, adăugat la sfârșitul fișierului:
+// corpul cererii nu este modificat, iar stația și momentul primirii sunt
+// păstrate în antetul gzip (Name, Comment)
+type payloadArchive struct {
+	dir       string
+	retention time.Duration // 0: datele nu sunt șterse
+
+	// Scrierile țin zilele ocupate (RLock), astfel încât o zi să nu fie ștearsă
+	// în timpul unei scrieri; stațiile diferite scriu în paralel
+	days sync.RWMutex
+	// Blocările fișierelor în care se scrie, după cale
+	mu    sync.Mutex
+	files map[string]*archiveFileLock
+}
+
+// Blocarea unui fișier al arhivei; cererile aceleiași stații din aceeași zi
+// sunt scrise pe rând, pentru ca membrii gzip să nu se amestece
+type archiveFileLock struct {
+	sync.Mutex
+	users int // numărul de scrieri care folosesc sau așteaptă blocarea
+}
+
+// Formatul directoarelor zilnice
+const archiveDayLayout = "2006-01-02"
+
+// Stația folosită pentru datele care nu au putut fi decodate
+const unknownStation = "necunoscuta"
+
+// Funcție pentru a crea arhiva; un director gol dezactivează arhivarea
+func newPayloadArchive(dir string, retention time.Duration) (*payloadArchive, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	err := os.MkdirAll(dir, 0750)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la crearea directorului arhivei: %w", err)
+	}
+	return &payloadArchive{dir: dir, retention: retention, files: make(map[string]*archiveFileLock)}, nil
+}
+
+// Funcție care blochează fișierul dat al arhivei și întoarce funcția de deblocare
+// Blocarea este eliminată când nicio scriere nu o mai folosește
+func (a *payloadArchive) lockFile(path string) func() {
+	a.mu.Lock()
+	lock := a.files[path]
+	if lock == nil {
+		lock = &archiveFileLock{}
+		a.files[path] = lock
+	}
+	lock.users++
+	a.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		a.mu.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(a.files, path)
+		}
+		a.mu.Unlock()
+	}
+}
+
+// Funcție care transformă cheia stației într-un nume de fișier sigur
+func archiveFileName(station string) string {
+	if station == "" {
+		station = unknownStation
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, station) + ".gz"
+}
+
+// Funcție care adaugă în arhivă corpul unei cereri primite de la o stație
+func (a *payloadArchive) store(station string, receivedAt time.Time, body []byte) error {
+	if a == nil {
+		return nil
+	}
+	receivedAt = receivedAt.UTC()
+	dayDir := filepath.Join(a.dir, receivedAt.Format(archiveDayLayout))
+	path := filepath.Join(dayDir, archiveFileName(station))
+
+	a.days.RLock()
+	defer a.days.RUnlock()
+	unlock := a.lockFile(path)
+	defer unlock()
+
+	err := os.MkdirAll(dayDir, 0750)
+	if err != nil {
+		return fmt.Errorf("eroare la crearea directorului arhivei: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return fmt.Errorf("eroare la deschiderea fișierului arhivei: %w", err)
+	}
+
+	writer := gzip.NewWriter(file)
+	writer.Name = station
+	writer.Comment = receivedAt.Format(time.RFC3339Nano)
+	writer.ModTime = receivedAt
+	_, err = writer.Write(body)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("eroare la scrierea în arhivă: %w", err)
+	}
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("eroare la scrierea în arhivă: %w", err)
+	}
+	return nil
+}
+
+// Funcție care șterge zilele mai vechi decât perioada de retenție
+func (a *payloadArchive) prune(now time.Time) error {
+	if a == nil || a.retention <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return fmt.Errorf("eroare la citirea directorului arhivei: %w", err)
+	}
+	limit := now.UTC().Add(-a.retention)
+	for _, entry := range entries {
+		day, err := time.Parse(archiveDayLayout, entry.Name())
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+		// O zi este ștearsă doar după ce toate datele din ea au depășit retenția
+		if day.AddDate(0, 0, 1).After(limit) {
+			continue
+		}
+		a.days.Lock()
+		err = os.RemoveAll(filepath.Join(a.dir, entry.Name()))
+		a.days.Unlock()
+		if err != nil {
+			return fmt.Errorf("eroare la ștergerea zilei %s din arhivă: %w", entry.Name(), err)
+		}
+		fmt.Printf("Arhiva: ziua %s a depășit retenția și a fost ștearsă\n", entry.Name())
+	}
+	return nil
+}
+
+// Funcție care aplică periodic retenția arhivei
+func (a *payloadArchive) pruneEvery(interval time.Duration) {
+	if a == nil || a.retention <= 0 {
+		return
+	}
+	for {
+		err := a.prune(time.Now())
+		if err != nil {
+			fmt.Printf("Eroare la aplicarea retenției arhivei: %v\n", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// O cerere păstrată în arhivă
+type archivedPayload struct {
+	Station    string
+	ReceivedAt time.Time
+	Body       []byte
+}
+
+// Funcție care citește, în ordine, cererile dintr-un fișier al arhivei
+func readArchiveFile(path string, fn func(archivedPayload) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("eroare la deschiderea fișierului arhivei: %w", err)
+	}
+	defer file.Close()
+
+	// Cititorul comun permite trecerea la membrul următor fără a pierde date
+	buffered := bufio.NewReader(file)
+	reader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return fmt.Errorf("eroare la citirea arhivei %s: %w", path, err)
+	}
+	defer reader.Close()
+
+	for {
+		// Fiecare membru gzip este citit separat, pentru a-i păstra antetul
+		reader.Multistream(false)
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			return fmt.Errorf("eroare la citirea arhivei %s: %w", path, err)
+		}
+		receivedAt, err := time.Parse(time.RFC3339Nano, reader.Comment)
+		if err != nil {
+			receivedAt = reader.ModTime
+		}
+		err = fn(archivedPayload{Station: reader.Name, ReceivedAt: receivedAt, Body: body})
+		if err != nil {
+			return err
+		}
+
+		err = reader.Reset(buffered)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("eroare la citirea arhivei %s: %w", path, err)
+		}
+	}
+}
+
+// Subcomanda 'arhiva': afișează cererile dintr-un fișier al arhivei
+// Cu -brut, corpurile sunt scrise exact cum au fost primite, pentru a fi retrimise
+func runArchiveCommand(args []string) error {
+	if len(args) < 1 || len(args) > 2 || (len(args) == 2 && args[0] != "-brut") {
+		return fmt.Errorf("utilizare: arhiva [-brut] <fișier>")
+	}
+	raw := len(args) == 2
+	return readArchiveFile(args[len(args)-1], func(p archivedPayload) error {
+		if raw {
+			_, err := os.Stdout.Write(append(p.Body, '\n'))
+			return err
+		}
+		fmt.Printf("%s  %s  %d octeți\n", p.ReceivedAt.Format(time.RFC3339Nano), p.Station, len(p.Body))
+		return nil
+	})
+}
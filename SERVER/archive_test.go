@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Cererile scrise în paralel, de la aceeași stație sau de la stații diferite,
+// sunt păstrate întregi, fiecare ca membru gzip separat
+func TestPayloadArchiveConcurrentStore(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := newPayloadArchive(dir, 0)
+	if err != nil {
+		t.Fatalf("newPayloadArchive: %v", err)
+	}
+	receivedAt := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	stations := []string{"id:A1", "id:B2", "adresa:10.0.0.5"}
+	const perStation = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(stations)*perStation)
+	for _, station := range stations {
+		for i := 0; i < perStation; i++ {
+			wg.Add(1)
+			go func(station string, i int) {
+				defer wg.Done()
+				body := []byte(fmt.Sprintf(`{"statie": %q, "secventa": %d}`, station, i))
+				errs <- archive.store(station, receivedAt.Add(time.Duration(i)*time.Millisecond), body)
+			}(station, i)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("store: %v", err)
+		}
+	}
+
+	for _, station := range stations {
+		path := filepath.Join(dir, "2024-05-10", archiveFileName(station))
+		seen := make(map[string]bool)
+		err := readArchiveFile(path, func(p archivedPayload) error {
+			if p.Station != station {
+				t.Errorf("%s: stația %q în antet", path, p.Station)
+			}
+			seen[string(p.Body)] = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("readArchiveFile: %v", err)
+		}
+		if len(seen) != perStation {
+			t.Errorf("%s: %d cereri distincte, așteptat %d", path, len(seen), perStation)
+		}
+	}
+	if len(archive.files) != 0 {
+		t.Errorf("au rămas %d blocări de fișiere după scrieri", len(archive.files))
+	}
+}
+
+// O zi este ștearsă doar după ce toate datele din ea au depășit retenția
+func TestPayloadArchivePrune(t *testing.T) {
+	dir := t.TempDir()
+	archive, err := newPayloadArchive(dir, 48*time.Hour)
+	if err != nil {
+		t.Fatalf("newPayloadArchive: %v", err)
+	}
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	for _, day := range []int{7, 8, 9} {
+		err = archive.store("id:A1", time.Date(2024, 5, day, 23, 0, 0, 0, time.UTC), []byte("{}"))
+		if err != nil {
+			t.Fatalf("store: %v", err)
+		}
+	}
+
+	err = archive.prune(now)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	for day, want := range map[string]bool{"2024-05-07": false, "2024-05-08": true, "2024-05-09": true} {
+		_, err := os.Stat(filepath.Join(dir, day))
+		if exists := err == nil; exists != want {
+			t.Errorf("ziua %s există: %v, așteptat %v", day, exists, want)
+		}
+	}
+}
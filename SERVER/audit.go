@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Istoricul metadatelor: modificările câmpurilor hardware și de sistem de operare
+// din 'metadate_statii' sunt scrise în 'modificari_metadate' de un trigger, așa că
+// sunt reținute indiferent de programul care actualizează metadatele
+//
+// Modificările suspecte (memorie RAM scăzută, placă de bază schimbată, adică altă
+// serie a plăcii de bază) sunt anunțate și pe canalul 'metadate_suspecte'; serverul
+// le afișează în jurnal
+
+// Canalul pe care baza de date anunță modificările suspecte
+const suspiciousChannel = "metadate_suspecte"
+
+// Structura pentru o modificare a metadatelor unei stații
+type metadataChange struct {
+	ID           int64     `json:"id_modificare"`
+	IDStatie     int       `json:"id_statie"`
+	NumeStatie   string    `json:"nume_statie"`
+	Moment       time.Time `json:"moment"`
+	Camp         string    `json:"camp"`
+	ValoareVeche *string   `json:"valoare_veche"`
+	ValoareNoua  *string   `json:"valoare_noua"`
+	Suspecta     bool      `json:"suspecta"`
+	Motiv        *string   `json:"motiv"`
+}
+
+const metadataChangeColumns = `m.id_modificare, m.id_statie, s.nume_statie, m.moment::timestamptz, m.camp,
+	m.valoare_veche, m.valoare_noua, m.suspecta, m.motiv`
+
+const metadataChangeFrom = `modificari_metadate m JOIN statii_de_lucru s ON s.id_statie = m.id_statie`
+
+// Coloanele după care pot fi ordonate modificările
+var metadataChangeSort = map[string]string{
+	"moment": "m.moment",
+	"statie": "s.nume_statie",
+	"camp":   "m.camp",
+}
+
+// Funcție care citește filtrele modificărilor din cerere și le adaugă la interogare
+//
+//	field    numele coloanei din 'metadate_statii', de exemplu memorie_ram
+//	suspect  true pentru a întoarce doar modificările suspecte
+func parseMetadataChangeFilter(r *http.Request, q *listQuery) error {
+	invalid := &validationError{}
+	values := r.URL.Query()
+	from, to := parseEventRange(values, invalid)
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		invalid.add("from", "trebuie să fie înainte de 'to'")
+	}
+	suspect := false
+	if value := values.Get("suspect"); value != "" {
+		var err error
+		suspect, err = strconv.ParseBool(value)
+		if err != nil {
+			invalid.add("suspect", "trebuie să fie true sau false")
+		}
+	}
+	if len(invalid.erori) > 0 {
+		return invalid
+	}
+
+	if field := values.Get("field"); field != "" {
+		q.where("m.camp = ?", field)
+	}
+	if suspect {
+		q.conditions = append(q.conditions, "m.suspecta")
+	}
+	if !from.IsZero() {
+		q.where("m.moment >= ?::timestamptz::timestamp", from)
+	}
+	if !to.IsZero() {
+		q.where("m.moment < ?::timestamptz::timestamp", to)
+	}
+	return nil
+}
+
+// Funcție care citește o pagină din istoricul metadatelor
+func queryMetadataChanges(ctx context.Context, db *sql.DB, q *listQuery) (int, []metadataChange, error) {
+	changes := []metadataChange{}
+	total, err := queryPage(ctx, db, metadataChangeFrom, metadataChangeColumns, q, func(rows *sql.Rows) error {
+		var c metadataChange
+		err := rows.Scan(&c.ID, &c.IDStatie, &c.NumeStatie, &c.Moment, &c.Camp, &c.ValoareVeche, &c.ValoareNoua, &c.Suspecta, &c.Motiv)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, c)
+		return nil
+	})
+	return total, changes, err
+}
+
+// GET /stations/{id}/metadata/changes?field=&suspect=&from=&to=
+func (api *queryAPI) listStationMetadataChanges(w http.ResponseWriter, r *http.Request) {
+	id, err := stationIDFromPath(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	q, err := parseListQuery(r, metadataChangeSort, "-moment", "m.id_modificare")
+	if err == nil {
+		err = parseMetadataChangeFilter(r, q)
+	}
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	var exists bool
+	err = api.db.QueryRowContext(r.Context(), "SELECT EXISTS (SELECT 1 FROM statii_de_lucru WHERE id_statie = $1)", id).Scan(&exists)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Stația %d nu există", id))
+		return
+	}
+
+	q.where("m.id_statie = ?", id)
+	total, changes, err := queryMetadataChanges(r.Context(), api.db, q)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: changes})
+}
+
+// GET /metadata/changes?field=&suspect=&from=&to=
+// Modificările tuturor stațiilor; suspect=true le întoarce doar pe cele suspecte
+func (api *queryAPI) listMetadataChanges(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, metadataChangeSort, "-moment", "m.id_modificare")
+	if err == nil {
+		err = parseMetadataChangeFilter(r, q)
+	}
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	total, changes, err := queryMetadataChanges(r.Context(), api.db, q)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: changes})
+}
+
+// Structura anunțului trimis de baza de date pentru o modificare suspectă
+type suspiciousChange struct {
+	IDStatie     int     `json:"id_statie"`
+	Camp         string  `json:"camp"`
+	ValoareVeche *string `json:"valoare_veche"`
+	ValoareNoua  *string `json:"valoare_noua"`
+	Motiv        string  `json:"motiv"`
+}
+
+// Funcție care afișează în jurnal modificările suspecte, pe măsură ce sunt confirmate
+// Conexiunea este refăcută automat dacă se pierde; anunțurile din acest timp se pierd,
+// dar modificările rămân în 'modificari_metadate'
+func watchSuspiciousChanges(ctx context.Context, databaseURL string) {
+	listener := pq.NewListener(databaseURL, 10*time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			fmt.Printf("Eroare la ascultarea modificărilor suspecte: %v\n", err)
+		}
+	})
+	defer listener.Close()
+
+	err := listener.Listen(suspiciousChannel)
+	if err != nil {
+		fmt.Printf("Eroare la ascultarea modificărilor suspecte: %v\n", err)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-listener.Notify:
+			// nil înseamnă că legătura a fost refăcută
+			if n == nil {
+				continue
+			}
+			var change suspiciousChange
+			err = json.Unmarshal([]byte(n.Extra), &change)
+			if err != nil {
+				fmt.Printf("Eroare la citirea modificării suspecte: %v\n", err)
+				continue
+			}
+			fmt.Printf("Avertisment: stația %d, %s: '%s' -> '%s' (%s)\n", change.IDStatie, change.Camp,
+				valueOrEmpty(change.ValoareVeche), valueOrEmpty(change.ValoareNoua), change.Motiv)
+		case <-time.After(90 * time.Second):
+			// Verifică periodic legătura, pentru a observa deconectările
+			go listener.Ping()
+		}
+	}
+}
+
+// Funcție care întoarce valoarea unui câmp opțional, sau "" dacă lipsește
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Antetul în care agentul trimite momentul trimiterii, după ceasul lui
+const sentAtHeader = "X-Trimis-La"
+
+// Limitele pentru momentul colectării trimis de agent
+const (
+	// Decalajul de ceas tolerat; peste această valoare momentul colectării este corectat
+	clockTolerance = 2 * time.Minute
+	// Eșantioanele mai vechi sunt respinse; limita este redusă dacă retenția
+	// metricilor este mai scurtă (vezi metricsRetention.maxSampleAge)
+	maxSampleAge = 30 * 24 * time.Hour
+)
+
+// Momentul colectării unui eșantion, așa cum este salvat în baza de date
+type sampleTime struct {
+	colectatLa time.Time     // momentul colectării, după ceasul serverului
+	secventa   int64         // numărul de secvență trimis de agent (0 dacă lipsește)
+	decalaj    time.Duration // decalajul ceasului agentului față de server
+	areDecalaj bool          // agentul a trimis momentul trimiterii
+}
+
+// Funcție care determină momentul colectării unui eșantion
+// Agentul trimite momentul colectării și momentul trimiterii, după ceasul lui;
+// diferența dintre momentul primirii și cel al trimiterii este decalajul ceasului
+// Datele fără moment al colectării, trimise de agenții mai vechi, primesc momentul primirii
+// Eșantioanele mai vechi decât maxAge sunt respinse
+func sampleTimeFromRequest(r *http.Request, p *Payload, receivedAt time.Time, maxAge time.Duration) (sampleTime, error) {
+	sample := sampleTime{colectatLa: receivedAt, secventa: p.Secventa}
+
+	if value := r.Header.Get(sentAtHeader); value != "" {
+		sentAt, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			invalid := &validationError{}
+			invalid.add(sentAtHeader, "antetul nu este un moment valid: %v", err)
+			return sample, invalid
+		}
+		sample.decalaj = receivedAt.Sub(sentAt)
+		sample.areDecalaj = true
+	}
+
+	if p.ColectatLa == nil {
+		return sample, nil
+	}
+	colectatLa := *p.ColectatLa
+
+	// Ceasul agentului este corectat doar dacă decalajul depășește toleranța
+	if sample.decalaj > clockTolerance || sample.decalaj < -clockTolerance {
+		colectatLa = colectatLa.Add(sample.decalaj)
+	}
+	if colectatLa.After(receivedAt.Add(clockTolerance)) {
+		invalid := &validationError{}
+		invalid.add("colectat_la", "momentul colectării %s este în viitor", colectatLa.Format(time.RFC3339))
+		return sample, invalid
+	}
+	if receivedAt.Sub(colectatLa) > maxAge {
+		invalid := &validationError{}
+		invalid.add("colectat_la", "momentul colectării %s este prea vechi", colectatLa.Format(time.RFC3339))
+		return sample, invalid
+	}
+	sample.colectatLa = colectatLa.UTC()
+
+	return sample, nil
+}
+
+// Funcție care reține decalajul ceasului și ultimul număr de secvență al stației
+// Afișează un avertisment dacă ceasul agentului este decalat sau lipsesc eșantioane
+func recordSampleTime(tx *sql.Tx, idStatie int, sample sampleTime) error {
+	if sample.areDecalaj && (sample.decalaj > clockTolerance || sample.decalaj < -clockTolerance) {
+		fmt.Printf("Ceasul stației cu ID %d este decalat cu %s\n", idStatie, sample.decalaj.Round(time.Second))
+	}
+
+	var ultimaSecventa sql.NullInt64
+	err := tx.QueryRow(`SELECT ultima_secventa FROM statii_de_lucru WHERE id_statie = $1`, idStatie).Scan(&ultimaSecventa)
+	if err != nil {
+		return fmt.Errorf("eroare la citirea ultimei secvențe a stației: %w", err)
+	}
+	if sample.secventa > 0 && ultimaSecventa.Valid && sample.secventa > ultimaSecventa.Int64+1 {
+		fmt.Printf("Lipsesc %d eșantioane de la stația cu ID %d\n", sample.secventa-ultimaSecventa.Int64-1, idStatie)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE statii_de_lucru SET
+			decalaj_ceas_ms = CASE WHEN $2 THEN $3 ELSE decalaj_ceas_ms END,
+			decalaj_ceas_la = CASE WHEN $2 THEN NOW() ELSE decalaj_ceas_la END,
+			ultima_secventa = GREATEST(COALESCE(ultima_secventa, 0), $4)
+		WHERE id_statie = $1
+	`, idStatie, sample.areDecalaj, sample.decalaj.Milliseconds(), sample.secventa)
+	if err != nil {
+		return fmt.Errorf("eroare la actualizarea ceasului stației: %w", err)
+	}
+	return nil
+}
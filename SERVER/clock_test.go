@@ -0,0 +1,121 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSampleTimeFromRequest(t *testing.T) {
+	receivedAt := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		value := receivedAt.Add(d)
+		return &value
+	}
+	maxAge := 7 * 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		sentAt     string
+		colectatLa *time.Time
+		want       time.Time
+		wantSkew   time.Duration
+		wantField  string
+	}{
+		{
+			name: "agent vechi, fără momente",
+			want: receivedAt,
+		},
+		{
+			name:       "fără antet",
+			colectatLa: at(-10 * time.Second),
+			want:       receivedAt.Add(-10 * time.Second),
+		},
+		{
+			name:       "decalaj sub toleranță, necorectat",
+			sentAt:     receivedAt.Add(-90 * time.Second).Format(time.RFC3339Nano),
+			colectatLa: at(-100 * time.Second),
+			want:       receivedAt.Add(-100 * time.Second),
+			wantSkew:   90 * time.Second,
+		},
+		{
+			name:       "ceasul agentului în urmă, corectat",
+			sentAt:     receivedAt.Add(-time.Hour).Format(time.RFC3339Nano),
+			colectatLa: at(-time.Hour - 5*time.Second),
+			want:       receivedAt.Add(-5 * time.Second),
+			wantSkew:   time.Hour,
+		},
+		{
+			name:       "ceasul agentului înainte, corectat",
+			sentAt:     receivedAt.Add(3 * time.Hour).Format(time.RFC3339Nano),
+			colectatLa: at(3*time.Hour - time.Minute),
+			want:       receivedAt.Add(-time.Minute),
+			wantSkew:   -3 * time.Hour,
+		},
+		{
+			name:       "eșantion din coadă, trimis după o zi",
+			sentAt:     receivedAt.Format(time.RFC3339Nano),
+			colectatLa: at(-24 * time.Hour),
+			want:       receivedAt.Add(-24 * time.Hour),
+		},
+		{
+			name:       "în viitor",
+			colectatLa: at(clockTolerance + time.Second),
+			wantField:  "colectat_la",
+		},
+		{
+			name:       "în viitor în limita toleranței",
+			colectatLa: at(clockTolerance),
+			want:       receivedAt.Add(clockTolerance),
+		},
+		{
+			name:       "prea vechi",
+			colectatLa: at(-maxAge - time.Second),
+			wantField:  "colectat_la",
+		},
+		{
+			name:       "prea vechi după corectare",
+			sentAt:     receivedAt.Add(time.Hour).Format(time.RFC3339Nano),
+			colectatLa: at(time.Hour - maxAge - time.Second),
+			wantField:  "colectat_la",
+		},
+		{
+			name:      "antet invalid",
+			sentAt:    "ieri la prânz",
+			wantField: sentAtHeader,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/data", nil)
+			if tt.sentAt != "" {
+				r.Header.Set(sentAtHeader, tt.sentAt)
+			}
+			p := &Payload{ColectatLa: tt.colectatLa, Secventa: 3}
+
+			sample, err := sampleTimeFromRequest(r, p, receivedAt, maxAge)
+			if tt.wantField != "" {
+				if err == nil {
+					t.Fatalf("eroare așteptată pentru %s, momentul %s", tt.wantField, sample.colectatLa)
+				}
+				fields := invalidFields(t, err)
+				if len(fields) != 1 || fields[0] != tt.wantField {
+					t.Errorf("câmpuri invalide %q, așteptat %q", fields, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sampleTimeFromRequest: %v", err)
+			}
+			if !sample.colectatLa.Equal(tt.want) {
+				t.Errorf("colectat la %s, așteptat %s", sample.colectatLa, tt.want)
+			}
+			if sample.decalaj != tt.wantSkew || sample.areDecalaj != (tt.sentAt != "") {
+				t.Errorf("decalaj %s (%v), așteptat %s", sample.decalaj, sample.areDecalaj, tt.wantSkew)
+			}
+			if sample.secventa != 3 {
+				t.Errorf("secvența %d, așteptat 3", sample.secventa)
+			}
+		})
+	}
+}
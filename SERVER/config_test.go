@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Funcție care scrie un fișier în directorul temporar al testului
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Ultima sursă câștigă: fișier, apoi mediu, apoi linia de comandă; un secret
+// citit dintr-un fișier are prioritatea sursei care a indicat fișierul
+func TestLoadServerConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	yamlURL := writeTestFile(t, dir, "url-yaml", "postgres://yaml@db/inventar\n")
+	envToken := writeTestFile(t, dir, "token-env", "token-din-fisier\n")
+	password := writeTestFile(t, dir, "parola", "s3cret\n")
+	config := writeTestFile(t, dir, "server.yaml", `baza_de_date:
+  url_fisier: `+yamlURL+`
+http:
+  adresa: ":9000"
+  token_agenti: token-yaml
+  max_corp_mb: 4
+`)
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		args      []string
+		wantURL   string
+		wantToken string
+		wantAddr  string
+		wantMaxMB int
+		wantArgs  []string
+	}{
+		{
+			name:      "fișier",
+			wantURL:   "postgres://yaml@db/inventar",
+			wantToken: "token-yaml",
+			wantAddr:  ":9000",
+			wantMaxMB: 4,
+		},
+		{
+			name:      "mediu peste fișier",
+			env:       map[string]string{"INVENTAR_DB_URL": "postgres://env@db/inventar", "INVENTAR_TOKEN_AGENTI_FISIER": envToken, "INVENTAR_MAX_CORP_MB": "8"},
+			wantURL:   "postgres://env@db/inventar",
+			wantToken: "token-din-fisier",
+			wantAddr:  ":9000",
+			wantMaxMB: 8,
+		},
+		{
+			name:      "linia de comandă peste mediu",
+			env:       map[string]string{"INVENTAR_TOKEN_AGENTI_FISIER": envToken, "INVENTAR_ADRESA": ":7000"},
+			args:      []string{"-token-agenti", "token-flag", "-adresa", ":6000", "migrate", "status"},
+			wantURL:   "postgres://yaml@db/inventar",
+			wantToken: "token-flag",
+			wantAddr:  ":6000",
+			wantMaxMB: 4,
+			wantArgs:  []string{"migrate", "status"},
+		},
+		{
+			name:      "parola din fișier peste șirul de conexiune",
+			env:       map[string]string{"INVENTAR_DB_PAROLA_FISIER": password},
+			args:      []string{"-db-url", "postgres://app:veche@db/inventar"},
+			wantURL:   "postgres://app:s3cret@db/inventar",
+			wantToken: "token-yaml",
+			wantAddr:  ":9000",
+			wantMaxMB: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INVENTAR_CONFIG", config)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, args, err := loadServerConfig(tt.args)
+			if err != nil {
+				t.Fatalf("loadServerConfig: %v", err)
+			}
+			if cfg.BazaDeDate.URL != tt.wantURL {
+				t.Errorf("baza_de_date.url = %q, așteptat %q", cfg.BazaDeDate.URL, tt.wantURL)
+			}
+			if cfg.HTTP.TokenAgenti != tt.wantToken {
+				t.Errorf("http.token_agenti = %q, așteptat %q", cfg.HTTP.TokenAgenti, tt.wantToken)
+			}
+			if cfg.HTTP.Adresa != tt.wantAddr {
+				t.Errorf("http.adresa = %q, așteptat %q", cfg.HTTP.Adresa, tt.wantAddr)
+			}
+			if cfg.HTTP.MaxCorpMB != tt.wantMaxMB {
+				t.Errorf("http.max_corp_mb = %d, așteptat %d", cfg.HTTP.MaxCorpMB, tt.wantMaxMB)
+			}
+			if strings.Join(args, " ") != strings.Join(tt.wantArgs, " ") {
+				t.Errorf("argumente = %v, așteptat %v", args, tt.wantArgs)
+			}
+			if cfg.Metrici.RetentieBrute != 7*24*time.Hour {
+				t.Errorf("metrici.retentie_brute = %s, așteptat valoarea implicită", cfg.Metrici.RetentieBrute)
+			}
+		})
+	}
+}
+
+func TestLoadServerConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	token := writeTestFile(t, dir, "token", "secret")
+
+	tests := []struct {
+		name    string
+		yaml    string
+		env     map[string]string
+		args    []string
+		wantErr []string
+	}{
+		{
+			name:    "valori invalide",
+			args:    []string{"-db-url", "mysql://db", "-izolare", "snapshot", "-max-corp-mb", "0", "-metrici-partitie", "luna"},
+			wantErr: []string{"baza_de_date.url", "baza_de_date.izolare", "http.max_corp_mb", "metrici.partitie"},
+		},
+		{
+			name:    "retenții prea scurte",
+			env:     map[string]string{"INVENTAR_METRICI_RETENTIE_BRUTE": "1m", "INVENTAR_METRICI_RETENTIE_1M": "1m", "INVENTAR_METRICI_RETENTIE_1H": "1m"},
+			wantErr: []string{"retențiile sunt prea scurte"},
+		},
+		{
+			name:    "cheie necunoscută",
+			yaml:    "http:\n  port: 8080\n",
+			wantErr: []string{"port"},
+		},
+		{
+			name:    "boolean invalid în mediu",
+			env:     map[string]string{"INVENTAR_AUTO_MIGRARE": "poate"},
+			wantErr: []string{"INVENTAR_AUTO_MIGRARE"},
+		},
+		{
+			name:    "token și fișier în aceeași sursă",
+			env:     map[string]string{"INVENTAR_TOKEN_AGENTI": "direct", "INVENTAR_TOKEN_AGENTI_FISIER": token},
+			wantErr: []string{"http.token_agenti și http.token_agenti_fisier"},
+		},
+		{
+			name:    "url și fișier în YAML",
+			yaml:    "baza_de_date:\n  url: postgres://db/inventar\n  url_fisier: " + token + "\n",
+			wantErr: []string{"baza_de_date.url și baza_de_date.url_fisier"},
+		},
+		{
+			name:    "fișierul secretului lipsește",
+			args:    []string{"-token-agenti-fisier", filepath.Join(dir, "lipsa")},
+			wantErr: []string{"eroare la citirea secretului"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := ""
+			if tt.yaml != "" {
+				config = writeTestFile(t, t.TempDir(), "server.yaml", tt.yaml)
+			}
+			t.Setenv("INVENTAR_CONFIG", config)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			_, _, err := loadServerConfig(tt.args)
+			if err == nil {
+				t.Fatal("loadServerConfig: eroare așteptată")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("eroarea %q nu conține %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ module Cpu
 go 1.22.4
 
 require (
-
-	github.com/lib/pq v1.10.9 // direct
-
+	github.com/lib/pq v1.10.9
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Istoricul software: la fiecare inventar, programele primite sunt comparate
+// cu cele active ale stației, iar diferențele sunt adăugate în 'evenimente_software'
+//
+//	instalat     un program care nu era activ pe stație
+//	actualizat   un program activ care apare cu altă versiune
+//	dezinstalat  un program activ care lipsește din inventar
+//
+// Primul inventar al unei stații nu produce evenimente, la fel ca un inventar gol
+const (
+	softwareInstalled   = "instalat"
+	softwareUpgraded    = "actualizat"
+	softwareUninstalled = "dezinstalat"
+)
+
+// Funcție care adaugă evenimentele software ale stației, comparând inventarul
+// din 'software_de_incarcat' cu programele active, înainte ca acestea să fie actualizate
+// Versiunile noi și vechi ale aceluiași program sunt asociate în ordinea versiunilor,
+// comparate numeric pe componente (cheie_versiune, migrarea 0009); cele care rămân
+// fără pereche devin instalări sau dezinstalări
+func recordSoftwareEvents(tx *sql.Tx, idStatie int) error {
+	_, err := tx.Exec(`
+		WITH primite AS (
+			SELECT DISTINCT nume, versiune FROM software_de_incarcat
+		), active AS (
+			SELECT nume, versiune FROM software_instalat
+			WHERE id_statie = $1 AND dezinstalat_la IS NULL
+		), noi AS (
+			SELECT nume, versiune,
+				row_number() OVER (PARTITION BY nume ORDER BY cheie_versiune(versiune) COLLATE "C", versiune) AS n
+			FROM primite p
+			WHERE NOT EXISTS (SELECT 1 FROM active a WHERE a.nume = p.nume AND a.versiune = p.versiune)
+		), disparute AS (
+			SELECT nume, versiune,
+				row_number() OVER (PARTITION BY nume ORDER BY cheie_versiune(versiune) COLLATE "C", versiune) AS n
+			FROM active a
+			WHERE NOT EXISTS (SELECT 1 FROM primite p WHERE p.nume = a.nume AND p.versiune = a.versiune)
+		)
+		INSERT INTO evenimente_software (id_statie, tip, nume, versiune_veche, versiune_noua)
+		SELECT $1::integer,
+			CASE WHEN d.nume IS NULL THEN $2::text WHEN n.nume IS NULL THEN $4::text ELSE $3::text END,
+			COALESCE(n.nume, d.nume), d.versiune, n.versiune
+		FROM noi n
+		FULL JOIN disparute d ON d.nume = n.nume AND d.n = n.n
+		WHERE EXISTS (SELECT 1 FROM software_instalat WHERE id_statie = $1)
+		ORDER BY COALESCE(n.nume, d.nume), COALESCE(n.n, d.n)
+	`, idStatie, softwareInstalled, softwareUpgraded, softwareUninstalled)
+	if err != nil {
+		return fmt.Errorf("eroare la înregistrarea istoricului software: %w", err)
+	}
+	return nil
+}
+
+// Structura pentru un eveniment din istoricul software
+type softwareEvent struct {
+	ID            int64     `json:"id_eveniment"`
+	IDStatie      int       `json:"id_statie"`
+	NumeStatie    string    `json:"nume_statie"`
+	Moment        time.Time `json:"moment"`
+	Tip           string    `json:"tip"`
+	Nume          string    `json:"nume"`
+	VersiuneVeche *string   `json:"versiune_veche"`
+	VersiuneNoua  *string   `json:"versiune_noua"`
+}
+
+const softwareEventColumns = `e.id_eveniment, e.id_statie, s.nume_statie, e.moment::timestamptz, e.tip,
+	e.nume, e.versiune_veche, e.versiune_noua`
+
+const softwareEventFrom = `evenimente_software e JOIN statii_de_lucru s ON s.id_statie = e.id_statie`
+
+// Coloanele după care pot fi ordonate evenimentele
+var softwareEventSort = map[string]string{
+	"moment": "e.moment",
+	"statie": "s.nume_statie",
+	"nume":   "e.nume",
+	"tip":    "e.tip",
+}
+
+// Filtrele istoricului software; valorile goale nu filtrează
+type softwareEventFilter struct {
+	idStatie int
+	nume     string // o parte din numele programului
+	tip      string
+	from, to time.Time
+}
+
+// Funcție care verifică filtrele și le adaugă la interogare
+func (f softwareEventFilter) apply(q *listQuery) error {
+	invalid := &validationError{}
+	switch f.tip {
+	case "", softwareInstalled, softwareUpgraded, softwareUninstalled:
+	default:
+		invalid.add("type", "tipul '%s' nu este cunoscut (acceptate: %s, %s, %s)", f.tip, softwareInstalled, softwareUpgraded, softwareUninstalled)
+	}
+	if !f.from.IsZero() && !f.to.IsZero() && !f.from.Before(f.to) {
+		invalid.add("from", "trebuie să fie înainte de 'to'")
+	}
+	if len(invalid.erori) > 0 {
+		return invalid
+	}
+
+	if f.idStatie != 0 {
+		q.where("e.id_statie = ?", f.idStatie)
+	}
+	if f.nume != "" {
+		q.contains("e.nume", f.nume)
+	}
+	if f.tip != "" {
+		q.where("e.tip = ?", f.tip)
+	}
+	if !f.from.IsZero() {
+		q.where("e.moment >= ?::timestamptz::timestamp", f.from)
+	}
+	if !f.to.IsZero() {
+		q.where("e.moment < ?::timestamptz::timestamp", f.to)
+	}
+	return nil
+}
+
+// Funcție care citește o pagină din istoricul software
+func querySoftwareEvents(ctx context.Context, db *sql.DB, q *listQuery) (int, []softwareEvent, error) {
+	events := []softwareEvent{}
+	total, err := queryPage(ctx, db, softwareEventFrom, softwareEventColumns, q, func(rows *sql.Rows) error {
+		var e softwareEvent
+		err := rows.Scan(&e.ID, &e.IDStatie, &e.NumeStatie, &e.Moment, &e.Tip, &e.Nume, &e.VersiuneVeche, &e.VersiuneNoua)
+		if err != nil {
+			return err
+		}
+		events = append(events, e)
+		return nil
+	})
+	return total, events, err
+}
+
+// Funcție care citește un moment dat ca RFC 3339 sau ca dată (2006-01-02, în UTC)
+func parseEventTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+	}
+	return t, err
+}
+
+// Funcție care citește intervalul from, to al unui istoric
+func parseEventRange(values url.Values, invalid *validationError) (from, to time.Time) {
+	var err error
+	if value := values.Get("from"); value != "" {
+		from, err = parseEventTime(value)
+		if err != nil {
+			invalid.add("from", "trebuie să fie un moment RFC 3339 sau o dată AAAA-LL-ZZ")
+		}
+	}
+	if value := values.Get("to"); value != "" {
+		to, err = parseEventTime(value)
+		if err != nil {
+			invalid.add("to", "trebuie să fie un moment RFC 3339 sau o dată AAAA-LL-ZZ")
+		}
+	}
+	return from, to
+}
+
+// Funcție care citește filtrele istoricului din cerere
+func parseSoftwareEventFilter(r *http.Request) (softwareEventFilter, error) {
+	invalid := &validationError{}
+	values := r.URL.Query()
+	f := softwareEventFilter{nume: values.Get("name"), tip: values.Get("type")}
+	f.from, f.to = parseEventRange(values, invalid)
+	if len(invalid.erori) > 0 {
+		return f, invalid
+	}
+	return f, nil
+}
+
+// GET /stations/{id}/software/events?name=&type=&from=&to=
+func (api *queryAPI) listStationSoftwareEvents(w http.ResponseWriter, r *http.Request) {
+	id, err := stationIDFromPath(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	f, err := parseSoftwareEventFilter(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	f.idStatie = id
+	q, err := parseListQuery(r, softwareEventSort, "-moment", "e.id_eveniment")
+	if err == nil {
+		err = f.apply(q)
+	}
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	var exists bool
+	err = api.db.QueryRowContext(r.Context(), "SELECT EXISTS (SELECT 1 FROM statii_de_lucru WHERE id_statie = $1)", id).Scan(&exists)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Stația %d nu există", id))
+		return
+	}
+
+	total, events, err := querySoftwareEvents(r.Context(), api.db, q)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: events})
+}
+
+// GET /software/events?name=&type=&from=&to=
+// Fără name, întoarce evenimentele tuturor stațiilor
+func (api *queryAPI) listSoftwareEvents(w http.ResponseWriter, r *http.Request) {
+	f, err := parseSoftwareEventFilter(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	q, err := parseListQuery(r, softwareEventSort, "-moment", "e.id_eveniment")
+	if err == nil {
+		err = f.apply(q)
+	}
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	total, events, err := querySoftwareEvents(r.Context(), api.db, q)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, page{Total: total, Limita: q.limit, Deplasare: q.offset, Elemente: events})
+}
+
+// Subcomanda 'istoric-software': afișează istoricul software, cele mai noi evenimente primele
+//
+//	Cpu istoric-software [-statie id] [-program nume] [-tip tip] [-de-la moment] [-pana-la moment] [-limita n]
+func runSoftwareHistoryCommand(db *sql.DB, args []string) error {
+	flags := flag.NewFlagSet("istoric-software", flag.ContinueOnError)
+	station := flags.Int("statie", 0, "ID-ul stației (implicit toate stațiile)")
+	program := flags.String("program", "", "o parte din numele programului")
+	kind := flags.String("tip", "", "tipul evenimentului (instalat, actualizat, dezinstalat)")
+	from := flags.String("de-la", "", "momentul de început, RFC 3339 sau AAAA-LL-ZZ")
+	to := flags.String("pana-la", "", "momentul de sfârșit, RFC 3339 sau AAAA-LL-ZZ")
+	limit := flags.Int("limita", 100, "numărul maxim de evenimente afișate")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("argument neașteptat: %s", flags.Arg(0))
+	}
+	if *station < 0 || *limit < 1 {
+		return fmt.Errorf("ID-ul stației și limita trebuie să fie pozitive")
+	}
+
+	f := softwareEventFilter{idStatie: *station, nume: *program, tip: *kind}
+	if *from != "" {
+		f.from, err = parseEventTime(*from)
+		if err != nil {
+			return fmt.Errorf("momentul de început nu este valid: %w", err)
+		}
+	}
+	if *to != "" {
+		f.to, err = parseEventTime(*to)
+		if err != nil {
+			return fmt.Errorf("momentul de sfârșit nu este valid: %w", err)
+		}
+	}
+	q := &listQuery{orderBy: "e.moment DESC, e.id_eveniment DESC", limit: *limit}
+	err = f.apply(q)
+	if err != nil {
+		return err
+	}
+
+	total, events, err := querySoftwareEvents(context.Background(), db, q)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		change := ""
+		switch {
+		case e.VersiuneVeche != nil && e.VersiuneNoua != nil:
+			change = *e.VersiuneVeche + " -> " + *e.VersiuneNoua
+		case e.VersiuneNoua != nil:
+			change = *e.VersiuneNoua
+		case e.VersiuneVeche != nil:
+			change = *e.VersiuneVeche
+		}
+		fmt.Printf("%s  %s (%d)  %-11s  %s  %s\n", e.Moment.Local().Format(time.DateTime), e.NumeStatie, e.IDStatie, e.Tip, e.Nume, change)
+	}
+	if total > len(events) {
+		fmt.Printf("Afișate %d din %d evenimente\n", len(events), total)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Structura pentru răspunsul trimis agentului după primirea inventarului
+type inventoryResponse struct {
+	Mesaj            string            `json:"mesaj"`
+	HashuriCunoscute map[string]string `json:"hashuri_cunoscute"`
+}
+
+// Funcție care salvează secțiunea de inventar cu numele dat
+// Secțiunea 'utilizator' nu este salvată, dar hash-ul ei este reținut
+func writeSection(tx *sql.Tx, sections *Sections, name string, idStatie int) error {
+	switch name {
+	case "sistem_de_operare":
+		return updateOSSection(tx, sections.SistemDeOperare, idStatie)
+	case "hardware":
+		return updateHardwareSection(tx, sections.Hardware, idStatie)
+	case "software":
+		return updateSoftwareSection(tx, sections.Software, idStatie)
+	case "securitate":
+		return updateSecuritySection(tx, *sections.Securitate, idStatie)
+	case "utilizator":
+		return nil
+	}
+	return fmt.Errorf("secțiunea '%s' nu este cunoscută", name)
+}
+
+// Funcție pentru a salva inventarul stației în 'metadate_statii' și 'software_instalat'
+// Inventarul fără hash-uri, trimis de agenții mai vechi, conține toate secțiunile
+func updateInventory(tx *sql.Tx, p *Payload, idStatie int) (map[string]string, error) {
+	if p.Hashuri == nil {
+		for _, section := range []string{"sistem_de_operare", "hardware", "software", "securitate"} {
+			err := writeSection(tx, &p.Sectiuni, section, idStatie)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	// Hash-urile secțiunilor salvate deja pentru stație
+	known, err := loadSectionHashes(tx, idStatie)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sunt salvate doar secțiunile trimise; celelalte ar trebui să fie deja cunoscute
+	for _, section := range inventorySections {
+		hash := p.Hashuri[section]
+		if !p.Sectiuni.has(section) || known[section] == hash {
+			// Dacă hash-ul este deja cunoscut, agentul nu a aflat încă de salvarea anterioară
+			continue
+		}
+		err = writeSection(tx, &p.Sectiuni, section, idStatie)
+		if err != nil {
+			return nil, err
+		}
+		err = saveSectionHash(tx, idStatie, section, hash)
+		if err != nil {
+			return nil, err
+		}
+		known[section] = hash
+	}
+
+	// Agentul primește doar hash-urile care corespund datelor lui
+	acknowledged := make(map[string]string)
+	for section, hash := range p.Hashuri {
+		if known[section] == hash {
+			acknowledged[section] = hash
+		}
+	}
+	return acknowledged, nil
+}
+
+// Funcție care încarcă hash-urile secțiunilor salvate pentru stație
+func loadSectionHashes(tx *sql.Tx, idStatie int) (map[string]string, error) {
+	rows, err := tx.Query(`SELECT sectiune, hash FROM hashuri_inventar WHERE id_statie = $1`, idStatie)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea hash-urilor inventarului: %w", err)
+	}
+	defer rows.Close()
+
+	known := make(map[string]string)
+	for rows.Next() {
+		var section, hash string
+		err = rows.Scan(&section, &hash)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la citirea hash-urilor inventarului: %w", err)
+		}
+		known[section] = hash
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("eroare la citirea hash-urilor inventarului: %w", err)
+	}
+	return known, nil
+}
+
+// Funcție care reține hash-ul unei secțiuni salvate
+func saveSectionHash(tx *sql.Tx, idStatie int, section, hash string) error {
+	_, err := tx.Exec(`
+		INSERT INTO hashuri_inventar (id_statie, sectiune, hash, actualizat_la)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (id_statie, sectiune) DO UPDATE SET
+			hash = EXCLUDED.hash,
+			actualizat_la = EXCLUDED.actualizat_la
+	`, idStatie, section, hash)
+	if err != nil {
+		return fmt.Errorf("eroare la salvarea hash-ului secțiunii '%s': %w", section, err)
+	}
+	return nil
+}
+
+// Funcție pentru a salva informațiile despre sistemul de operare în 'metadate_statii'
+// Versiunea kernel-ului este trimisă doar de agenții Linux; lipsa ei se salvează ca NULL
+func updateOSSection(tx *sql.Tx, osInfo *OSInfo, idStatie int) error {
+	_, err := tx.Exec(`
+		INSERT INTO metadate_statii (
+			id_statie, sistem_operare, versiune_software, arhitectura_sistem_operare,
+			data_instalare_sistem_operare, licenta_sistem_operare, versiune_kernel
+		) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		ON CONFLICT (id_statie) DO UPDATE SET 
+			sistem_operare = EXCLUDED.sistem_operare,
+			versiune_software = EXCLUDED.versiune_software,
+			arhitectura_sistem_operare = EXCLUDED.arhitectura_sistem_operare,
+			data_instalare_sistem_operare = EXCLUDED.data_instalare_sistem_operare,
+			licenta_sistem_operare = EXCLUDED.licenta_sistem_operare,
+			versiune_kernel = EXCLUDED.versiune_kernel
+	`, idStatie, osInfo.Nume, osInfo.Versiune, osInfo.Arhitectura,
+		osInfo.DataInstalarii, osInfo.Licenta, osInfo.Kernel)
+	if err != nil {
+		return fmt.Errorf("eroare la actualizarea metadatelor stației: %w", err)
+	}
+	return nil
+}
+
+// Funcție pentru a salva informațiile despre hardware în 'metadate_statii'
+// Modelul discului, BIOS-ul și seria plăcii de bază lipsesc la agenții mai vechi și se
+// salvează atunci ca NULL
+func updateHardwareSection(tx *sql.Tx, hardwareInfo *HardwareInfo, idStatie int) error {
+	_, err := tx.Exec(`
+		INSERT INTO metadate_statii (
+			id_statie, producator_procesor, model_procesor, nuclee, 
+			fire_executie, frecventa, memorie_ram, tip_stocare, 
+			capacitate_stocare, placa_de_baza, placa_video, model_stocare, bios, serial_placa_de_baza
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), NULLIF($13, ''), NULLIF($14, ''))
+		ON CONFLICT (id_statie) DO UPDATE SET 
+			producator_procesor = EXCLUDED.producator_procesor,
+			model_procesor = EXCLUDED.model_procesor,
+			nuclee = EXCLUDED.nuclee,
+			fire_executie = EXCLUDED.fire_executie,
+			frecventa = EXCLUDED.frecventa,
+			memorie_ram = EXCLUDED.memorie_ram,
+			tip_stocare = EXCLUDED.tip_stocare,
+			capacitate_stocare = EXCLUDED.capacitate_stocare,
+			placa_de_baza = EXCLUDED.placa_de_baza,
+			placa_video = EXCLUDED.placa_video,
+			model_stocare = EXCLUDED.model_stocare,
+			bios = EXCLUDED.bios,
+			serial_placa_de_baza = EXCLUDED.serial_placa_de_baza
+	`, idStatie, strings.Split(hardwareInfo.PlacaDeBaza, " ")[0], hardwareInfo.Procesor, hardwareInfo.Nuclee,
+		hardwareInfo.FireExecutie, hardwareInfo.Frecventa, hardwareInfo.MemorieRAM, hardwareInfo.TipStocare,
+		hardwareInfo.CapacitateHDD, hardwareInfo.PlacaDeBaza, hardwareInfo.PlacaVideo, hardwareInfo.ModelStocare,
+		hardwareInfo.BIOS, hardwareInfo.SerialPlacaDeBaza)
+	if err != nil {
+		return fmt.Errorf("eroare la actualizarea metadatelor stației: %w", err)
+	}
+	return nil
+}
+
+// Funcție pentru a salva starea securității în 'metadate_statii'
+func updateSecuritySection(tx *sql.Tx, securityInfo string, idStatie int) error {
+	_, err := tx.Exec(`
+		INSERT INTO metadate_statii (id_statie, securitate)
+		VALUES ($1, $2)
+		ON CONFLICT (id_statie) DO UPDATE SET 
+			securitate = EXCLUDED.securitate
+	`, idStatie, securityInfo)
+	if err != nil {
+		return fmt.Errorf("eroare la actualizarea metadatelor stației: %w", err)
+	}
+	return nil
+}
+
+// Funcție pentru a salva programele instalate în 'software_instalat'
+// Programele sunt încărcate cu COPY într-un tabel temporar și combinate cu
+// tabelul principal printr-o singură instrucțiune, indiferent de numărul lor
+// Inventarul este complet: programele stației care lipsesc din el sunt marcate
+// ca dezinstalate, iar cele care reapar sunt marcate din nou ca instalate
+func updateSoftwareSection(tx *sql.Tx, softwareInfo *SoftwareInfo, idStatie int) error {
+	err := createSoftwareStaging(tx)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(pq.CopyIn("software_de_incarcat", "ordine", "nume", "versiune", "producator", "data_instalare", "licenta", "sursa"))
+	if err != nil {
+		return fmt.Errorf("eroare la pregătirea încărcării software-ului instalat: %w", err)
+	}
+	for i, program := range softwareInfo.ProgrameInstalate {
+		_, err = stmt.Exec(i, program.Nume, program.Versiune, program.Producator, program.DataInstalare, program.Licenta, program.Sursa)
+		if err != nil {
+			stmt.Close()
+			return fmt.Errorf("eroare la încărcarea software-ului instalat: %w", err)
+		}
+	}
+	_, err = stmt.Exec()
+	if err != nil {
+		stmt.Close()
+		return fmt.Errorf("eroare la încărcarea software-ului instalat: %w", err)
+	}
+	err = stmt.Close()
+	if err != nil {
+		return fmt.Errorf("eroare la încărcarea software-ului instalat: %w", err)
+	}
+
+	// Istoricul este calculat înainte ca programele stației să fie actualizate;
+	// un inventar gol nu produce evenimente, la fel ca mai jos
+	if len(softwareInfo.ProgrameInstalate) > 0 {
+		err = recordSoftwareEvents(tx, idStatie)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Pentru programele trimise de mai multe ori se păstrează ultima apariție,
+	// iar rândurile care nu s-au schimbat nu sunt rescrise
+	_, err = tx.Exec(`
+		INSERT INTO software_instalat (id_statie, nume, versiune, producator, data_instalare, licenta, sursa)
+		SELECT DISTINCT ON (nume, versiune) $1::integer, nume, versiune, producator, data_instalare, licenta, sursa
+		FROM software_de_incarcat
+		ORDER BY nume, versiune, ordine DESC
+		ON CONFLICT (id_statie, nume, versiune) DO UPDATE SET
+		producator = EXCLUDED.producator,
+		data_instalare = EXCLUDED.data_instalare,
+		licenta = EXCLUDED.licenta,
+		sursa = EXCLUDED.sursa,
+		dezinstalat_la = NULL
+		WHERE (software_instalat.producator, software_instalat.data_instalare, software_instalat.licenta, software_instalat.sursa)
+			IS DISTINCT FROM (EXCLUDED.producator, EXCLUDED.data_instalare, EXCLUDED.licenta, EXCLUDED.sursa)
+			OR software_instalat.dezinstalat_la IS NOT NULL
+	`, idStatie)
+	if err != nil {
+		return fmt.Errorf("eroare la actualizarea software-ului instalat: %w", err)
+	}
+
+	// O listă goală înseamnă de obicei că agentul nu a putut citi programele,
+	// așa că nu este folosită pentru a marca programe ca dezinstalate
+	if len(softwareInfo.ProgrameInstalate) > 0 {
+		err = markUninstalledSoftware(tx, idStatie)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`TRUNCATE software_de_incarcat`)
+	if err != nil {
+		return fmt.Errorf("eroare la golirea tabelului temporar pentru software: %w", err)
+	}
+	return nil
+}
+
+// Funcție care creează tabelul temporar în care este încărcat inventarul software
+// Tabelul aparține conexiunii; este golit după fiecare folosire
+func createSoftwareStaging(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TEMP TABLE IF NOT EXISTS software_de_incarcat (
+			ordine INTEGER NOT NULL,
+			nume TEXT NOT NULL,
+			versiune TEXT NOT NULL,
+			producator TEXT,
+			data_instalare TEXT,
+			licenta TEXT,
+			sursa TEXT
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("eroare la crearea tabelului temporar pentru software: %w", err)
+	}
+	return nil
+}
+
+// Funcție care marchează ca dezinstalate programele stației care lipsesc din 'software_de_incarcat'
+func markUninstalledSoftware(tx *sql.Tx, idStatie int) error {
+	_, err := tx.Exec(`
+		UPDATE software_instalat si SET dezinstalat_la = NOW()
+		WHERE si.id_statie = $1 AND si.dezinstalat_la IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM software_de_incarcat s
+				WHERE s.nume = si.nume AND s.versiune = si.versiune
+			)
+	`, idStatie)
+	if err != nil {
+		return fmt.Errorf("eroare la marcarea software-ului dezinstalat: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+)
+
+// Benchmark-urile scrierii inventarului software rulează pe baza de date dată
+// în INVENTAR_TEST_DB_URL, căreia îi sunt aplicate migrările; fiecare iterație
+// rulează într-o tranzacție anulată la final, cu o persoană și o stație temporare
+//
+//	INVENTAR_TEST_DB_URL=postgres://... go test -run '^$' -bench UpdateSoftwareSection
+const testDatabaseEnv = "INVENTAR_TEST_DB_URL"
+
+// Numărul de programe din inventarul de test, cât are o stație Windows obișnuită
+const benchmarkProgramCount = 400
+
+// Funcție care deschide baza de date de test sau oprește benchmark-ul dacă lipsește
+func openTestDatabase(b *testing.B) *sql.DB {
+	b.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		b.Skipf("%s nu este setată", testDatabaseEnv)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+	err = migrateUp(db)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return db
+}
+
+// Funcție care începe tranzacția unei iterații și creează stația temporară
+func beginBenchmarkTx(b *testing.B, db *sql.DB) (*sql.Tx, int) {
+	b.Helper()
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	var idStatie int
+	err = tx.QueryRow(`
+		WITH persoana AS (
+			INSERT INTO persoane (nume) VALUES ('benchmark') RETURNING id_persoana
+		)
+		INSERT INTO statii_de_lucru (nume_statie, id_persoana)
+		SELECT 'benchmark', id_persoana FROM persoana
+		RETURNING id_statie
+	`).Scan(&idStatie)
+	if err != nil {
+		tx.Rollback()
+		b.Fatalf("eroare la crearea stației de test: %v", err)
+	}
+	return tx, idStatie
+}
+
+// Funcție care generează un inventar de test, asemănător celui unei stații Windows
+func benchmarkSoftware(count int) *SoftwareInfo {
+	software := &SoftwareInfo{ProgrameInstalate: make([]ProgramInfo, count)}
+	for i := range software.ProgrameInstalate {
+		software.ProgrameInstalate[i] = ProgramInfo{
+			Nume:          fmt.Sprintf("Program de test %04d", i),
+			Versiune:      fmt.Sprintf("%d.%d.%d", i%7+1, i%13, i),
+			Producator:    fmt.Sprintf("Producător %02d", i%40),
+			DataInstalare: fmt.Sprintf("2024%02d%02d", i%12+1, i%28+1),
+			Licenta:       "N/A",
+			Sursa:         "wmic",
+		}
+	}
+	return software
+}
+
+// Funcție pentru a salva programele instalate câte unul pe rând, cu aceleași
+// efecte ca updateSoftwareSection (istoric, programe dezinstalate); diferă doar
+// încărcarea și combinarea, care folosesc câte o instrucțiune pentru fiecare program
+func updateSoftwareSectionPerRow(tx *sql.Tx, softwareInfo *SoftwareInfo, idStatie int) error {
+	err := createSoftwareStaging(tx)
+	if err != nil {
+		return err
+	}
+
+	stage, err := tx.Prepare(`
+		INSERT INTO software_de_incarcat (ordine, nume, versiune, producator, data_instalare, licenta, sursa)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		return fmt.Errorf("eroare la pregătirea încărcării software-ului instalat: %w", err)
+	}
+	defer stage.Close()
+	for i, program := range softwareInfo.ProgrameInstalate {
+		_, err = stage.Exec(i, program.Nume, program.Versiune, program.Producator, program.DataInstalare, program.Licenta, program.Sursa)
+		if err != nil {
+			return fmt.Errorf("eroare la încărcarea software-ului instalat: %w", err)
+		}
+	}
+
+	if len(softwareInfo.ProgrameInstalate) > 0 {
+		err = recordSoftwareEvents(tx, idStatie)
+		if err != nil {
+			return err
+		}
+	}
+
+	upsert, err := tx.Prepare(`
+		INSERT INTO software_instalat (id_statie, nume, versiune, producator, data_instalare, licenta, sursa)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id_statie, nume, versiune) DO UPDATE SET
+		producator = EXCLUDED.producator,
+		data_instalare = EXCLUDED.data_instalare,
+		licenta = EXCLUDED.licenta,
+		sursa = EXCLUDED.sursa,
+		dezinstalat_la = NULL
+		WHERE (software_instalat.producator, software_instalat.data_instalare, software_instalat.licenta, software_instalat.sursa)
+			IS DISTINCT FROM (EXCLUDED.producator, EXCLUDED.data_instalare, EXCLUDED.licenta, EXCLUDED.sursa)
+			OR software_instalat.dezinstalat_la IS NOT NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("eroare la pregătirea actualizării software-ului instalat: %w", err)
+	}
+	defer upsert.Close()
+	for _, program := range softwareInfo.ProgrameInstalate {
+		_, err = upsert.Exec(idStatie, program.Nume, program.Versiune, program.Producator, program.DataInstalare, program.Licenta, program.Sursa)
+		if err != nil {
+			return fmt.Errorf("eroare la actualizarea software-ului instalat: %w", err)
+		}
+	}
+
+	if len(softwareInfo.ProgrameInstalate) > 0 {
+		err = markUninstalledSoftware(tx, idStatie)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`TRUNCATE software_de_incarcat`)
+	if err != nil {
+		return fmt.Errorf("eroare la golirea tabelului temporar pentru software: %w", err)
+	}
+	return nil
+}
+
+// Funcție care măsoară scrierea inventarului: prima scriere pentru o stație nouă
+// și rescrierea aceluiași inventar, cazul obișnuit când agentul retrimite datele
+func benchmarkSoftwareWriter(b *testing.B, write func(tx *sql.Tx, softwareInfo *SoftwareInfo, idStatie int) error) {
+	db := openTestDatabase(b)
+	software := benchmarkSoftware(benchmarkProgramCount)
+
+	for _, rewrite := range []bool{false, true} {
+		name := "inserare"
+		if rewrite {
+			name = "rescriere"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				tx, idStatie := beginBenchmarkTx(b, db)
+				if rewrite {
+					err := write(tx, software, idStatie)
+					if err != nil {
+						tx.Rollback()
+						b.Fatal(err)
+					}
+				}
+				b.StartTimer()
+
+				err := write(tx, software, idStatie)
+
+				b.StopTimer()
+				tx.Rollback()
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkUpdateSoftwareSectionCopy(b *testing.B) {
+	benchmarkSoftwareWriter(b, updateSoftwareSection)
+}
+
+func BenchmarkUpdateSoftwareSectionPerRow(b *testing.B) {
+	benchmarkSoftwareWriter(b, updateSoftwareSectionPerRow)
+}
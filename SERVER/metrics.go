@@ -0,0 +1,365 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Interogarea metricilor, agregate pe intervale în SQL
+//
+//	GET /stations/{id}/metrics?from=&to=&step=&agg=   seria unei stații
+//	GET /metrics/top?from=&to=&agg=&metric=&n=        stațiile cele mai încărcate
+//
+// from și to sunt momente RFC 3339 (implicit: ultima oră), step este o durată
+// (de exemplu 5m; implicit aleasă după interval), iar agg este avg, max sau p95
+// Traficul de rețea este trimis de agent ca total cumulat, așa că pentru fiecare
+// interval se întoarce diferența dintre prima și ultima valoare, nu agregarea cerută
+//
+// Datele sunt citite din eșantioanele brute sau din agregatele pe minut, oră sau
+// zi (rollup.go): se alege cel mai grosier tabel care are încă datele cerute și
+// al cărui interval se împarte exact în pas; p95 este calculat din eșantioanele
+// brute cât timp acestea există, apoi aproximat din maximele intervalelor
+
+// Funcțiile de agregare acceptate
+var metricAggregates = map[string]string{
+	"avg": "AVG(%s)",
+	"max": "MAX(%s)",
+	"p95": "percentile_cont(0.95) WITHIN GROUP (ORDER BY %s)",
+}
+
+// Metricile după care pot fi ordonate stațiile
+var metricOrder = map[string]string{
+	"cpu": "valoare_cpu",
+	"ram": "valoare_ram",
+}
+
+// Sursa unei interogări de metrici: eșantioanele brute sau un tabel de agregate
+type metricSource struct {
+	table      string
+	timeColumn string
+	bucket     time.Duration // 0 pentru eșantioanele brute
+}
+
+// Funcție care întoarce sursele, de la cea mai fină la cea mai grosieră
+func metricSources() []metricSource {
+	sources := []metricSource{{table: "metrici_statii", timeColumn: "timestamp"}}
+	for _, rollup := range metricRollups {
+		sources = append(sources, metricSource{table: rollup.table, timeColumn: "moment", bucket: rollup.bucket})
+	}
+	return sources
+}
+
+// Funcție care întoarce expresia de agregare pentru coloana dată
+func (s metricSource) aggregateOf(aggregate, column string) string {
+	if s.bucket == 0 {
+		return fmt.Sprintf(metricAggregates[aggregate], column)
+	}
+	switch aggregate {
+	case "avg":
+		return fmt.Sprintf("SUM(%s_medie * esantioane) / NULLIF(SUM(esantioane), 0)", column)
+	case "max":
+		return fmt.Sprintf("MAX(%s_max)", column)
+	}
+	return fmt.Sprintf(metricAggregates[aggregate], column+"_max")
+}
+
+// Funcție care întoarce traficul dintr-un interval, din totalurile cumulate
+func (s metricSource) trafficOf(column string) string {
+	if s.bucket == 0 {
+		return fmt.Sprintf("MAX(%[1]s) - MIN(%[1]s)", column)
+	}
+	return fmt.Sprintf("MAX(%[1]s_max) - MIN(%[1]s_min)", column)
+}
+
+// Funcție care întoarce numărul de eșantioane dintr-un interval
+func (s metricSource) samples() string {
+	if s.bucket == 0 {
+		return "COUNT(*)"
+	}
+	return "SUM(esantioane)"
+}
+
+// Funcție care alege sursa pentru interogarea dată; dacă datele fine au fost
+// șterse, pasul este mărit la intervalul sursei alese
+func (q *metricsQuery) source(retention metricsRetention, now time.Time) metricSource {
+	sources := metricSources()
+	covers := func(s metricSource) bool {
+		keep := retention.of(s.table)
+		return keep <= 0 || !q.from.Before(now.Add(-keep))
+	}
+	// p95 este exact doar din eșantioanele brute
+	if q.aggregate == "p95" && covers(sources[0]) {
+		return sources[0]
+	}
+	for i := len(sources) - 1; i >= 0; i-- {
+		s := sources[i]
+		if (s.bucket == 0 || q.step%s.bucket == 0) && covers(s) {
+			return s
+		}
+	}
+	for _, s := range sources[1:] {
+		if covers(s) || s.bucket == sources[len(sources)-1].bucket {
+			if q.step < s.bucket {
+				q.step = s.bucket
+			}
+			return s
+		}
+	}
+	return sources[0]
+}
+
+// Pașii aleși automat, astfel încât o serie să aibă cel mult în jur de 300 de puncte
+var metricSteps = []time.Duration{
+	10 * time.Second, 30 * time.Second, time.Minute, 5 * time.Minute, 15 * time.Minute,
+	30 * time.Minute, time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// Limitele unei interogări de metrici
+const (
+	defaultMetricsRange = time.Hour
+	targetMetricPoints  = 300
+	maxMetricPoints     = 5000
+	defaultTopStations  = 10
+	maxTopStations      = 100
+)
+
+// Parametrii comuni ai interogărilor de metrici
+type metricsQuery struct {
+	from      time.Time
+	to        time.Time
+	step      time.Duration
+	aggregate string
+}
+
+// Funcție care alege pasul implicit pentru intervalul dat
+func defaultMetricStep(window time.Duration) time.Duration {
+	for _, step := range metricSteps {
+		if window/step <= targetMetricPoints {
+			return step
+		}
+	}
+	return metricSteps[len(metricSteps)-1]
+}
+
+// Funcție care citește intervalul, pasul și agregarea din cerere
+func parseMetricsQuery(r *http.Request, now time.Time) (*metricsQuery, error) {
+	invalid := &validationError{}
+	values := r.URL.Query()
+	q := &metricsQuery{to: now.UTC(), aggregate: "avg"}
+
+	if value := values.Get("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalid.add("to", "trebuie să fie un moment RFC 3339")
+		}
+		q.to = to.UTC()
+	}
+	q.from = q.to.Add(-defaultMetricsRange)
+	if value := values.Get("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalid.add("from", "trebuie să fie un moment RFC 3339")
+		}
+		q.from = from.UTC()
+	}
+	if !q.from.Before(q.to) {
+		invalid.add("from", "trebuie să fie înainte de 'to'")
+	}
+
+	if value := values.Get("agg"); value != "" {
+		if _, ok := metricAggregates[value]; !ok {
+			invalid.add("agg", "agregarea '%s' nu este cunoscută (acceptate: avg, max, p95)", value)
+		}
+		q.aggregate = value
+	}
+
+	q.step = defaultMetricStep(q.to.Sub(q.from))
+	if value := values.Get("step"); value != "" {
+		step, err := time.ParseDuration(value)
+		switch {
+		case err != nil || step < time.Second:
+			invalid.add("step", "trebuie să fie o durată de cel puțin 1s")
+		case q.to.Sub(q.from)/step > maxMetricPoints:
+			invalid.add("step", "intervalul cerut ar avea peste %d de puncte", maxMetricPoints)
+		}
+		q.step = step
+	}
+
+	if len(invalid.erori) > 0 {
+		return nil, invalid
+	}
+	return q, nil
+}
+
+// Un punct din seria de metrici
+type metricPoint struct {
+	Moment            time.Time `json:"moment"`
+	UtilizareCPU      *float64  `json:"utilizare_cpu"`
+	UtilizareRAM      *float64  `json:"utilizare_ram"`
+	TraficTrimis      *int64    `json:"trafic_retea_bytes_trimisi"`
+	TraficReceptionat *int64    `json:"trafic_retea_bytes_primiti"`
+	Esantioane        int       `json:"esantioane"`
+}
+
+// Seria de metrici a unei stații
+type metricSeries struct {
+	IDStatie   int           `json:"id_statie"`
+	DeLa       time.Time     `json:"de_la"`
+	PanaLa     time.Time     `json:"pana_la"`
+	PasSecunde float64       `json:"pas_secunde"`
+	Agregare   string        `json:"agregare"`
+	Sursa      string        `json:"sursa"`
+	Puncte     []metricPoint `json:"puncte"`
+}
+
+// GET /stations/{id}/metrics
+func (api *queryAPI) stationMetrics(w http.ResponseWriter, r *http.Request) {
+	id, err := stationIDFromPath(r)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	now := time.Now()
+	q, err := parseMetricsQuery(r, now)
+	if err != nil {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+	source := q.source(api.retention, now)
+
+	var exists bool
+	err = api.db.QueryRowContext(r.Context(), "SELECT EXISTS (SELECT 1 FROM statii_de_lucru WHERE id_statie = $1)", id).Scan(&exists)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Stația %d nu există", id))
+		return
+	}
+
+	// Intervalele sunt aliniate la multipli ai pasului de la începutul epocii Unix
+	// Coloanele de timp nu au fus orar, așa că limitele sunt convertite la fel ca la inserare
+	rows, err := api.db.QueryContext(r.Context(), fmt.Sprintf(`
+		SELECT (TIMESTAMP 'epoch' + floor(extract(epoch FROM %[2]s)::double precision / $4::double precision) * $4::double precision * INTERVAL '1 second')::timestamptz AS interval_pas,
+			%[3]s, %[4]s, %[5]s, %[6]s, %[7]s
+		FROM %[1]s
+		WHERE id_statie = $1 AND %[2]s >= $2::timestamptz::timestamp AND %[2]s < $3::timestamptz::timestamp
+		GROUP BY interval_pas
+		ORDER BY interval_pas
+	`, source.table, source.timeColumn,
+		source.aggregateOf(q.aggregate, "utilizare_cpu"), source.aggregateOf(q.aggregate, "utilizare_memorie"),
+		source.trafficOf("trafic_retea_bytes_trimisi"), source.trafficOf("trafic_retea_bytes_primiti"),
+		source.samples()), id, q.from, q.to, q.step.Seconds())
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer rows.Close()
+
+	series := metricSeries{IDStatie: id, DeLa: q.from, PanaLa: q.to, PasSecunde: q.step.Seconds(), Agregare: q.aggregate, Sursa: source.table, Puncte: []metricPoint{}}
+	for rows.Next() {
+		var p metricPoint
+		err = rows.Scan(&p.Moment, &p.UtilizareCPU, &p.UtilizareRAM, &p.TraficTrimis, &p.TraficReceptionat, &p.Esantioane)
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		p.Moment = p.Moment.UTC()
+		series.Puncte = append(series.Puncte, p)
+	}
+	if err = rows.Err(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, series)
+}
+
+// O stație din clasamentul după încărcare
+type stationLoad struct {
+	IDStatie     int      `json:"id_statie"`
+	NumeStatie   string   `json:"nume_statie"`
+	UtilizareCPU *float64 `json:"utilizare_cpu"`
+	UtilizareRAM *float64 `json:"utilizare_ram"`
+	Esantioane   int      `json:"esantioane"`
+}
+
+// Clasamentul stațiilor pe un interval
+type topStations struct {
+	DeLa     time.Time     `json:"de_la"`
+	PanaLa   time.Time     `json:"pana_la"`
+	Agregare string        `json:"agregare"`
+	Metrica  string        `json:"metrica"`
+	Sursa    string        `json:"sursa"`
+	Statii   []stationLoad `json:"statii"`
+}
+
+// GET /metrics/top
+func (api *queryAPI) topStations(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	q, err := parseMetricsQuery(r, now)
+	invalid := &validationError{}
+	if err != nil && !errors.As(err, &invalid) {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", err)
+		return
+	}
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "cpu"
+	}
+	orderBy, ok := metricOrder[metric]
+	if !ok {
+		invalid.add("metric", "metrica '%s' nu este cunoscută (acceptate: cpu, ram)", metric)
+	}
+	n := defaultTopStations
+	if value := r.URL.Query().Get("n"); value != "" {
+		n, err = strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxTopStations {
+			invalid.add("n", "trebuie să fie un număr între 1 și %d", maxTopStations)
+		}
+	}
+	if len(invalid.erori) > 0 {
+		writeBadRequest(w, "Parametrii cererii nu sunt valizi", invalid)
+		return
+	}
+
+	source := q.source(api.retention, now)
+	rows, err := api.db.QueryContext(r.Context(), fmt.Sprintf(`
+		SELECT s.id_statie, s.nume_statie, %[3]s AS valoare_cpu, %[4]s AS valoare_ram, %[5]s
+		FROM %[1]s m
+		JOIN statii_de_lucru s ON s.id_statie = m.id_statie
+		WHERE m.%[2]s >= $1::timestamptz::timestamp AND m.%[2]s < $2::timestamptz::timestamp
+		GROUP BY s.id_statie, s.nume_statie
+		ORDER BY %[6]s DESC NULLS LAST, s.id_statie
+		LIMIT $3
+	`, source.table, source.timeColumn,
+		source.aggregateOf(q.aggregate, "m.utilizare_cpu"), source.aggregateOf(q.aggregate, "m.utilizare_memorie"),
+		source.samples(), orderBy), q.from, q.to, n)
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	defer rows.Close()
+
+	top := topStations{DeLa: q.from, PanaLa: q.to, Agregare: q.aggregate, Metrica: metric, Sursa: source.table, Statii: []stationLoad{}}
+	for rows.Next() {
+		var s stationLoad
+		err = rows.Scan(&s.IDStatie, &s.NumeStatie, &s.UtilizareCPU, &s.UtilizareRAM, &s.Esantioane)
+		if err != nil {
+			writeInternalError(w, err)
+			return
+		}
+		top.Statii = append(top.Statii, s)
+	}
+	if err = rows.Err(); err != nil {
+		writeInternalError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, top)
+}
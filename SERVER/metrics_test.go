@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Sursa metricilor este cel mai grosier tabel care are încă datele cerute și al
+// cărui interval se împarte exact în pas; altfel pasul este mărit
+func TestMetricsQuerySource(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	retention := metricsRetention{raw: 7 * day, minute: 30 * day, hour: 365 * day}
+
+	tests := []struct {
+		name      string
+		age       time.Duration // vechimea începutului intervalului
+		step      time.Duration
+		aggregate string
+		wantTable string
+		wantStep  time.Duration
+	}{
+		{name: "pas sub un minut", age: time.Hour, step: 10 * time.Second, aggregate: "avg", wantTable: "metrici_statii", wantStep: 10 * time.Second},
+		{name: "pas de un minut", age: time.Hour, step: time.Minute, aggregate: "avg", wantTable: "metrici_statii_1m", wantStep: time.Minute},
+		{name: "pas de 5 minute", age: 2 * day, step: 5 * time.Minute, aggregate: "max", wantTable: "metrici_statii_1m", wantStep: 5 * time.Minute},
+		{name: "pas de o oră", age: 10 * day, step: time.Hour, aggregate: "avg", wantTable: "metrici_statii_1h", wantStep: time.Hour},
+		{name: "pas de o zi", age: 400 * day, step: day, aggregate: "avg", wantTable: "metrici_statii_1d", wantStep: day},
+		{name: "eșantioane brute șterse", age: 10 * day, step: 10 * time.Second, aggregate: "avg", wantTable: "metrici_statii_1m", wantStep: time.Minute},
+		{name: "agregate pe minut șterse", age: 60 * day, step: 10 * time.Second, aggregate: "avg", wantTable: "metrici_statii_1h", wantStep: time.Hour},
+		{name: "agregate pe oră șterse", age: 400 * day, step: time.Minute, aggregate: "max", wantTable: "metrici_statii_1d", wantStep: day},
+		{name: "p95 exact", age: time.Hour, step: time.Hour, aggregate: "p95", wantTable: "metrici_statii", wantStep: time.Hour},
+		{name: "p95 aproximat", age: 10 * day, step: time.Hour, aggregate: "p95", wantTable: "metrici_statii_1h", wantStep: time.Hour},
+		{name: "la limita retenției", age: 7 * day, step: 10 * time.Second, aggregate: "avg", wantTable: "metrici_statii", wantStep: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &metricsQuery{from: now.Add(-tt.age), to: now, step: tt.step, aggregate: tt.aggregate}
+			source := q.source(retention, now)
+			if source.table != tt.wantTable || q.step != tt.wantStep {
+				t.Errorf("sursa %s, pas %s; așteptat %s, %s", source.table, q.step, tt.wantTable, tt.wantStep)
+			}
+		})
+	}
+
+	// Fără retenție, datele brute sunt păstrate pentru totdeauna
+	q := &metricsQuery{from: now.Add(-400 * day), to: now, step: 10 * time.Second, aggregate: "avg"}
+	if source := q.source(metricsRetention{}, now); source.table != "metrici_statii" {
+		t.Errorf("fără retenție: sursa %s, așteptat metrici_statii", source.table)
+	}
+}
+
+// Parametrii invalizi sunt raportați toți, fără a ajunge la baza de date
+func TestTopStationsInvalid(t *testing.T) {
+	api := newQueryAPI(nil, metricsRetention{}, "")
+	rec := httptest.NewRecorder()
+	api.topStations(rec, httptest.NewRequest("GET", "/metrics/top?agg=median&metric=disc&n=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("cod = %d, așteptat %d: %s", rec.Code, http.StatusBadRequest, rec.Body.String())
+	}
+	for _, field := range []string{`"agg"`, `"metric"`, `"n"`} {
+		if !strings.Contains(rec.Body.String(), field) {
+			t.Errorf("răspunsul %s nu conține câmpul %s", rec.Body.String(), field)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migrările schemei, incluse în executabil
+// Fiecare migrare are un fișier <versiune>_<nume>.up.sql și unul .down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// O migrare a schemei
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// Funcție care încarcă migrările incluse în executabil, în ordinea versiunilor
+func loadMigrations() ([]migration, error) {
+	files, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea migrărilor: %w", err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, file := range files {
+		base := strings.TrimPrefix(file, "migrations/")
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+			base = strings.TrimSuffix(base, ".up.sql")
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+			base = strings.TrimSuffix(base, ".down.sql")
+		default:
+			return nil, fmt.Errorf("fișierul de migrare '%s' nu se termină cu .up.sql sau .down.sql", file)
+		}
+
+		prefix, name, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("fișierul de migrare '%s' nu începe cu o versiune: %w", file, err)
+		}
+		content, err := migrationFiles.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la citirea migrării '%s': %w", file, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: name}
+			byVersion[version] = m
+		}
+		if direction == "up" {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" || m.down == "" {
+			return nil, fmt.Errorf("migrarea %04d_%s nu are ambele fișiere .up.sql și .down.sql", m.version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+	return migrations, nil
+}
+
+// Cheia lock-ului de migrare ("inventar" în ASCII), luat de migrateUp și
+// migrateDown pentru ca două servere pornite deodată să nu aplice aceeași migrare
+const migrationLockKey int64 = 0x696e76656e746172
+
+// Conexiunea pe care rulează migrările: baza de date, sau o singură conexiune
+// când lock-ul de migrare trebuie păstrat pe toată durata migrării
+type migrationDB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Funcție care rulează fn pe o conexiune dedicată, cu lock-ul de migrare luat
+// Lock-ul este eliberat la final sau, dacă eliberarea eșuează, la închiderea conexiunii
+func withMigrationLock(db *sql.DB, fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("eroare la deschiderea conexiunii pentru migrare: %w", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey)
+	if err != nil {
+		return fmt.Errorf("eroare la obținerea lock-ului de migrare: %w", err)
+	}
+	defer func() {
+		_, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+		if err != nil {
+			// Lock-ul nu trebuie să rămână pe o conexiune refolosită din pool
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	return fn(conn)
+}
+
+// Funcție care creează tabelul cu versiunile aplicate, dacă lipsește
+func ensureSchemaVersionTable(db migrationDB) error {
+	_, err := db.ExecContext(context.Background(), `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			versiune INTEGER PRIMARY KEY,
+			nume TEXT NOT NULL,
+			aplicata_la TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("eroare la crearea tabelului schema_version: %w", err)
+	}
+	return nil
+}
+
+// Funcție care întoarce versiunile aplicate deja
+func appliedVersions(db migrationDB) (map[int]bool, error) {
+	err := ensureSchemaVersionTable(db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(context.Background(), `SELECT versiune FROM schema_version`)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea versiunilor schemei: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		err = rows.Scan(&version)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la citirea versiunilor schemei: %w", err)
+		}
+		applied[version] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("eroare la citirea versiunilor schemei: %w", err)
+	}
+	return applied, nil
+}
+
+// Funcție care aplică o migrare și versiunea ei într-o singură tranzacție
+func runMigration(db migrationDB, m migration, up bool) error {
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("eroare la începerea tranzacției: %w", err)
+	}
+	defer tx.Rollback()
+
+	if up {
+		_, err = tx.Exec(m.up)
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO schema_version (versiune, nume) VALUES ($1, $2)`, m.version, m.name)
+		}
+	} else {
+		_, err = tx.Exec(m.down)
+		if err == nil {
+			_, err = tx.Exec(`DELETE FROM schema_version WHERE versiune = $1`, m.version)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("eroare la migrarea %04d_%s: %w", m.version, m.name, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("eroare la confirmarea migrării %04d_%s: %w", m.version, m.name, err)
+	}
+	return nil
+}
+
+// Funcție care aplică toate migrările care nu au fost aplicate încă
+// Versiunile aplicate sunt citite după obținerea lock-ului, pentru a vedea
+// migrările aplicate între timp de alt server
+func migrateUp(db *sql.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if applied[m.version] {
+				continue
+			}
+			err = runMigration(conn, m, true)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Migrarea %04d_%s a fost aplicată\n", m.version, m.name)
+		}
+		return nil
+	})
+}
+
+// Funcție care anulează ultimele n migrări aplicate
+func migrateDown(db *sql.DB, n int) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && n > 0; i-- {
+			m := migrations[i]
+			if !applied[m.version] {
+				continue
+			}
+			err = runMigration(conn, m, false)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Migrarea %04d_%s a fost anulată\n", m.version, m.name)
+			n--
+		}
+		return nil
+	})
+}
+
+// Funcție care afișează starea fiecărei migrări
+func migrateStatus(db *sql.DB) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		state := "neaplicată"
+		if applied[m.version] {
+			state = "aplicată"
+		}
+		fmt.Printf("%04d_%s: %s\n", m.version, m.name, state)
+	}
+	return nil
+}
+
+// Funcție care rulează subcomanda 'migrate up|down [n]|status'
+func runMigrateCommand(db *sql.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("utilizare: migrate up | down [n] | status")
+	}
+
+	switch args[0] {
+	case "up":
+		return migrateUp(db)
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			n, err = strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("numărul de migrări de anulat nu este valid: %s", args[1])
+			}
+		}
+		return migrateDown(db, n)
+	case "status":
+		return migrateStatus(db)
+	}
+	return fmt.Errorf("subcomanda 'migrate %s' nu este cunoscută (up, down, status)", args[0])
+}
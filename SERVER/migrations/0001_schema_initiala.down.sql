@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS metrici_statii;
+DROP TABLE IF EXISTS software_instalat;
+DROP TABLE IF EXISTS metadate_statii;
+DROP TABLE IF EXISTS statii_de_lucru;
+DROP TABLE IF EXISTS persoane;
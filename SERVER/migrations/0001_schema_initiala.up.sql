@@ -0,0 +1,62 @@
+-- Schema inițială a bazei de date de inventar
+-- Tabelele pot exista deja în bazele de date create înaintea migrărilor
+
+CREATE TABLE IF NOT EXISTS persoane (
+    id_persoana SERIAL PRIMARY KEY,
+    nume TEXT NOT NULL
+);
+
+-- Stațiile noi sunt asociate persoanei cu ID-ul 1
+INSERT INTO persoane (id_persoana, nume) VALUES (1, 'Neatribuit')
+ON CONFLICT (id_persoana) DO NOTHING;
+SELECT setval(pg_get_serial_sequence('persoane', 'id_persoana'), GREATEST((SELECT MAX(id_persoana) FROM persoane), 1));
+
+CREATE TABLE IF NOT EXISTS statii_de_lucru (
+    id_statie SERIAL PRIMARY KEY,
+    nume_statie TEXT NOT NULL,
+    id_persoana INTEGER NOT NULL REFERENCES persoane (id_persoana)
+);
+
+CREATE TABLE IF NOT EXISTS metadate_statii (
+    id_statie INTEGER PRIMARY KEY REFERENCES statii_de_lucru (id_statie),
+    producator_procesor TEXT,
+    model_procesor TEXT,
+    nuclee INTEGER,
+    fire_executie INTEGER,
+    frecventa TEXT,
+    memorie_ram TEXT,
+    tip_stocare TEXT,
+    capacitate_stocare TEXT,
+    placa_de_baza TEXT,
+    placa_video TEXT,
+    sistem_operare TEXT,
+    versiune_software TEXT,
+    arhitectura_sistem_operare TEXT,
+    data_instalare_sistem_operare TEXT,
+    licenta_sistem_operare TEXT,
+    securitate TEXT
+);
+
+CREATE TABLE IF NOT EXISTS software_instalat (
+    id_software SERIAL PRIMARY KEY,
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    nume TEXT NOT NULL,
+    versiune TEXT NOT NULL DEFAULT '',
+    producator TEXT,
+    data_instalare TEXT,
+    licenta TEXT,
+    UNIQUE (id_statie, nume, versiune)
+);
+
+CREATE TABLE IF NOT EXISTS metrici_statii (
+    id_metrica BIGSERIAL PRIMARY KEY,
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    timestamp TIMESTAMP NOT NULL,
+    utilizare_cpu DOUBLE PRECISION,
+    utilizare_memorie DOUBLE PRECISION,
+    trafic_retea_bytes_trimisi BIGINT,
+    trafic_retea_bytes_primiti BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS metrici_statii_statie_timestamp_idx
+    ON metrici_statii (id_statie, timestamp);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS statii_de_lucru_identificator_statie_idx;
+ALTER TABLE statii_de_lucru DROP COLUMN IF EXISTS identificator_statie;
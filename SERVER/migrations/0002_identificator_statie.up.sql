@@ -0,0 +1,4 @@
+-- Identificatorul trimis de agent, după care este găsită stația
+ALTER TABLE statii_de_lucru ADD COLUMN IF NOT EXISTS identificator_statie TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS statii_de_lucru_identificator_statie_idx
+    ON statii_de_lucru (identificator_statie);
@@ -0,0 +1 @@
+ALTER TABLE software_instalat DROP COLUMN IF EXISTS sursa;
@@ -0,0 +1,2 @@
+-- Sursa programului instalat (wmic, dpkg, rpm, snap, flatpak)
+ALTER TABLE software_instalat ADD COLUMN IF NOT EXISTS sursa TEXT;
@@ -0,0 +1,8 @@
+-- Hash-ul ultimei versiuni salvate a fiecărei secțiuni de inventar
+CREATE TABLE IF NOT EXISTS hashuri_inventar (
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    sectiune TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    actualizat_la TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id_statie, sectiune)
+);
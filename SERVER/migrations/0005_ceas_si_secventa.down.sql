@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS metrici_statii_statie_timestamp_secventa_idx;
+ALTER TABLE metrici_statii DROP COLUMN IF EXISTS secventa;
+ALTER TABLE metrici_statii DROP COLUMN IF EXISTS primit_la;
+ALTER TABLE statii_de_lucru DROP COLUMN IF EXISTS ultima_secventa;
+ALTER TABLE statii_de_lucru DROP COLUMN IF EXISTS decalaj_ceas_la;
+ALTER TABLE statii_de_lucru DROP COLUMN IF EXISTS decalaj_ceas_ms;
@@ -0,0 +1,11 @@
+-- Decalajul ceasului agentului și ultimul număr de secvență primit
+ALTER TABLE statii_de_lucru ADD COLUMN IF NOT EXISTS decalaj_ceas_ms BIGINT;
+ALTER TABLE statii_de_lucru ADD COLUMN IF NOT EXISTS decalaj_ceas_la TIMESTAMP;
+ALTER TABLE statii_de_lucru ADD COLUMN IF NOT EXISTS ultima_secventa BIGINT;
+
+-- Momentul primirii și numărul de secvență al fiecărei metrici; coloana
+-- 'timestamp' păstrează momentul colectării
+ALTER TABLE metrici_statii ADD COLUMN IF NOT EXISTS primit_la TIMESTAMP;
+ALTER TABLE metrici_statii ADD COLUMN IF NOT EXISTS secventa BIGINT;
+CREATE UNIQUE INDEX IF NOT EXISTS metrici_statii_statie_timestamp_secventa_idx
+    ON metrici_statii (id_statie, timestamp, secventa);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS stare_agregare_metrici;
+DROP INDEX IF EXISTS metrici_statii_timestamp_idx;
+DROP INDEX IF EXISTS metrici_statii_primit_la_idx;
+DROP TABLE IF EXISTS metrici_statii_1d;
+DROP TABLE IF EXISTS metrici_statii_1h;
+DROP TABLE IF EXISTS metrici_statii_1m;
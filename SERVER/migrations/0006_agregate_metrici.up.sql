@@ -0,0 +1,40 @@
+-- Agregatele metricilor pe minut, oră și zi, calculate de server din 'metrici_statii'
+-- Traficul este un total cumulat, așa că se păstrează prima și ultima valoare (min, max)
+
+CREATE TABLE IF NOT EXISTS metrici_statii_1m (
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    moment TIMESTAMP NOT NULL,
+    esantioane INTEGER NOT NULL,
+    utilizare_cpu_min DOUBLE PRECISION,
+    utilizare_cpu_medie DOUBLE PRECISION,
+    utilizare_cpu_max DOUBLE PRECISION,
+    utilizare_memorie_min DOUBLE PRECISION,
+    utilizare_memorie_medie DOUBLE PRECISION,
+    utilizare_memorie_max DOUBLE PRECISION,
+    trafic_retea_bytes_trimisi_min BIGINT,
+    trafic_retea_bytes_trimisi_max BIGINT,
+    trafic_retea_bytes_primiti_min BIGINT,
+    trafic_retea_bytes_primiti_max BIGINT,
+    actualizat_la TIMESTAMP NOT NULL,
+    PRIMARY KEY (id_statie, moment)
+);
+
+CREATE TABLE IF NOT EXISTS metrici_statii_1h (LIKE metrici_statii_1m INCLUDING ALL);
+CREATE TABLE IF NOT EXISTS metrici_statii_1d (LIKE metrici_statii_1m INCLUDING ALL);
+
+CREATE INDEX IF NOT EXISTS metrici_statii_1m_moment_idx ON metrici_statii_1m (moment);
+CREATE INDEX IF NOT EXISTS metrici_statii_1h_moment_idx ON metrici_statii_1h (moment);
+CREATE INDEX IF NOT EXISTS metrici_statii_1d_moment_idx ON metrici_statii_1d (moment);
+
+-- Eșantioanele sunt agregate în ordinea primirii, inclusiv cele sosite târziu din coada agentului
+UPDATE metrici_statii SET primit_la = timestamp WHERE primit_la IS NULL;
+CREATE INDEX IF NOT EXISTS metrici_statii_primit_la_idx ON metrici_statii (primit_la);
+CREATE INDEX IF NOT EXISTS metrici_statii_timestamp_idx ON metrici_statii (timestamp);
+
+-- Momentul primirii până la care eșantioanele au fost agregate
+CREATE TABLE IF NOT EXISTS stare_agregare_metrici (
+    nume TEXT PRIMARY KEY,
+    procesat_pana_la TIMESTAMP NOT NULL
+);
+INSERT INTO stare_agregare_metrici (nume, procesat_pana_la) VALUES ('metrici_statii', TIMESTAMP 'epoch')
+ON CONFLICT (nume) DO NOTHING;
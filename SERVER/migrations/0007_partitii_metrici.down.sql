@@ -0,0 +1,16 @@
+-- Datele din toate partițiile sunt copiate înapoi într-un tabel simplu
+ALTER SEQUENCE metrici_statii_id_metrica_seq OWNED BY NONE;
+
+CREATE TABLE metrici_statii_simplu (LIKE metrici_statii INCLUDING DEFAULTS);
+INSERT INTO metrici_statii_simplu SELECT * FROM metrici_statii;
+DROP TABLE metrici_statii CASCADE;
+ALTER TABLE metrici_statii_simplu RENAME TO metrici_statii;
+
+ALTER TABLE metrici_statii ADD PRIMARY KEY (id_metrica);
+ALTER TABLE metrici_statii ADD FOREIGN KEY (id_statie) REFERENCES statii_de_lucru (id_statie);
+ALTER SEQUENCE metrici_statii_id_metrica_seq OWNED BY metrici_statii.id_metrica;
+
+CREATE INDEX metrici_statii_statie_timestamp_idx ON metrici_statii (id_statie, timestamp);
+CREATE UNIQUE INDEX metrici_statii_statie_timestamp_secventa_idx ON metrici_statii (id_statie, timestamp, secventa);
+CREATE INDEX metrici_statii_primit_la_idx ON metrici_statii (primit_la);
+CREATE INDEX metrici_statii_timestamp_idx ON metrici_statii (timestamp);
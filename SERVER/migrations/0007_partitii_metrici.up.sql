@@ -0,0 +1,48 @@
+-- 'metrici_statii' devine un tabel partiționat după momentul colectării
+-- Tabelul existent este atașat ca partiție pentru tot trecutul, până la sfârșitul
+-- zilei curente, fără a copia datele; partițiile următoare (pe zi sau pe
+-- săptămână) sunt create și șterse de server (partitions.go)
+
+ALTER TABLE metrici_statii RENAME TO metrici_statii_vechi;
+
+-- Partiția trebuie să aibă aceeași cheie primară ca tabelul partiționat
+ALTER TABLE metrici_statii_vechi DROP CONSTRAINT metrici_statii_pkey;
+ALTER TABLE metrici_statii_vechi ADD CONSTRAINT metrici_statii_vechi_pkey PRIMARY KEY (id_metrica, timestamp);
+ALTER INDEX IF EXISTS metrici_statii_statie_timestamp_idx RENAME TO metrici_statii_vechi_statie_timestamp_idx;
+ALTER INDEX IF EXISTS metrici_statii_statie_timestamp_secventa_idx RENAME TO metrici_statii_vechi_statie_timestamp_secventa_idx;
+ALTER INDEX IF EXISTS metrici_statii_primit_la_idx RENAME TO metrici_statii_vechi_primit_la_idx;
+ALTER INDEX IF EXISTS metrici_statii_timestamp_idx RENAME TO metrici_statii_vechi_timestamp_idx;
+
+-- Cheia primară a unui tabel partiționat trebuie să conțină cheia de partiționare
+CREATE TABLE metrici_statii (
+    id_metrica BIGINT NOT NULL DEFAULT nextval('metrici_statii_id_metrica_seq'),
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    timestamp TIMESTAMP NOT NULL,
+    utilizare_cpu DOUBLE PRECISION,
+    utilizare_memorie DOUBLE PRECISION,
+    trafic_retea_bytes_trimisi BIGINT,
+    trafic_retea_bytes_primiti BIGINT,
+    primit_la TIMESTAMP,
+    secventa BIGINT,
+    PRIMARY KEY (id_metrica, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+-- Secvența aparține tabelului nou, ca să nu fie ștearsă împreună cu partiția veche
+ALTER SEQUENCE metrici_statii_id_metrica_seq OWNED BY metrici_statii.id_metrica;
+
+CREATE INDEX metrici_statii_statie_timestamp_idx ON metrici_statii (id_statie, timestamp);
+CREATE UNIQUE INDEX metrici_statii_statie_timestamp_secventa_idx ON metrici_statii (id_statie, timestamp, secventa);
+CREATE INDEX metrici_statii_primit_la_idx ON metrici_statii (primit_la);
+
+-- Eșantioanele din afara partițiilor existente ajung în partiția implicită
+CREATE TABLE metrici_statii_implicit PARTITION OF metrici_statii DEFAULT;
+
+DO $$
+DECLARE
+    sfarsit TIMESTAMP;
+BEGIN
+    SELECT GREATEST(date_trunc('day', LOCALTIMESTAMP), date_trunc('day', MAX(timestamp))) + INTERVAL '1 day'
+    INTO sfarsit FROM metrici_statii_vechi;
+    EXECUTE format('ALTER TABLE metrici_statii ATTACH PARTITION metrici_statii_vechi FOR VALUES FROM (MINVALUE) TO (%L)', sfarsit);
+END
+$$;
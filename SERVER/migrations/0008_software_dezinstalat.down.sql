@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS software_instalat_instalate_idx;
+ALTER TABLE software_instalat DROP COLUMN IF EXISTS dezinstalat_la;
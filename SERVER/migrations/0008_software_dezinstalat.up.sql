@@ -0,0 +1,5 @@
+-- Programele care lipsesc din ultimul inventar al stației sunt marcate ca
+-- dezinstalate, nu șterse; NULL înseamnă că programul este instalat
+ALTER TABLE software_instalat ADD COLUMN IF NOT EXISTS dezinstalat_la TIMESTAMP;
+CREATE INDEX IF NOT EXISTS software_instalat_instalate_idx
+    ON software_instalat (id_statie) WHERE dezinstalat_la IS NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS evenimente_software;
+DROP FUNCTION IF EXISTS evenimente_software_doar_adaugare();
+DROP FUNCTION IF EXISTS cheie_versiune(TEXT);
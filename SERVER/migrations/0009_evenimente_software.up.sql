@@ -0,0 +1,36 @@
+-- Istoricul modificărilor software pe fiecare stație: programe instalate,
+-- actualizate (de la o versiune la alta) și dezinstalate
+-- Tabelul este doar pentru adăugare; modificarea sau ștergerea rândurilor este refuzată
+CREATE TABLE IF NOT EXISTS evenimente_software (
+    id_eveniment BIGSERIAL PRIMARY KEY,
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    moment TIMESTAMP NOT NULL DEFAULT NOW(),
+    tip TEXT NOT NULL CHECK (tip IN ('instalat', 'actualizat', 'dezinstalat')),
+    nume TEXT NOT NULL,
+    versiune_veche TEXT,
+    versiune_noua TEXT
+);
+
+CREATE INDEX IF NOT EXISTS evenimente_software_statie_moment_idx ON evenimente_software (id_statie, moment);
+CREATE INDEX IF NOT EXISTS evenimente_software_nume_moment_idx ON evenimente_software (nume, moment);
+
+-- Cheia după care sunt ordonate versiunile unui program: secvențele de cifre sunt
+-- completate cu zerouri, astfel încât '9.0' < '10.0' și '1.2.9' < '1.2.10'
+-- Cheile sunt comparate cu COLLATE "C", independent de colaționarea bazei de date
+CREATE OR REPLACE FUNCTION cheie_versiune(versiune TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(
+        CASE WHEN parte[1] ~ '^[0-9]+$' THEN lpad(parte[1], greatest(20, length(parte[1])), '0') ELSE parte[1] END,
+        '' ORDER BY nr), '')
+    FROM regexp_matches(versiune, '[0-9]+|[^0-9]+', 'g') WITH ORDINALITY AS m(parte, nr)
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION evenimente_software_doar_adaugare() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'evenimente_software permite doar adăugarea de rânduri';
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS evenimente_software_doar_adaugare ON evenimente_software;
+CREATE TRIGGER evenimente_software_doar_adaugare
+    BEFORE UPDATE OR DELETE ON evenimente_software
+    FOR EACH ROW EXECUTE FUNCTION evenimente_software_doar_adaugare();
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS metadate_statii_modificari ON metadate_statii;
+DROP FUNCTION IF EXISTS metadate_statii_modificari();
+DROP TABLE IF EXISTS campuri_metadate_urmarite;
+DROP TABLE IF EXISTS modificari_metadate;
+DROP FUNCTION IF EXISTS modificari_metadate_doar_adaugare();
@@ -0,0 +1,109 @@
+-- Istoricul modificărilor din 'metadate_statii': pentru fiecare câmp hardware sau
+-- de sistem de operare schimbat se păstrează valoarea veche, valoarea nouă și momentul
+-- Modificările suspecte (memorie RAM scăzută, placă de bază schimbată) sunt marcate
+-- și anunțate pe canalul 'metadate_suspecte', la confirmarea tranzacției
+CREATE TABLE IF NOT EXISTS modificari_metadate (
+    id_modificare BIGSERIAL PRIMARY KEY,
+    id_statie INTEGER NOT NULL REFERENCES statii_de_lucru (id_statie),
+    moment TIMESTAMP NOT NULL DEFAULT NOW(),
+    camp TEXT NOT NULL,
+    valoare_veche TEXT,
+    valoare_noua TEXT,
+    suspecta BOOLEAN NOT NULL DEFAULT FALSE,
+    motiv TEXT
+);
+
+CREATE INDEX IF NOT EXISTS modificari_metadate_statie_moment_idx ON modificari_metadate (id_statie, moment);
+CREATE INDEX IF NOT EXISTS modificari_metadate_suspecte_idx ON modificari_metadate (moment) WHERE suspecta;
+
+-- Câmpurile din 'metadate_statii' urmărite în 'modificari_metadate' și regula după
+-- care o modificare este suspectă:
+--   scadere    valoarea numerică de la începutul câmpului a scăzut (de exemplu 8 GB -> 4 GB)
+--   inlocuire  o valoare cunoscută a fost înlocuită cu altă valoare cunoscută
+-- Migrările care adaugă coloane în 'metadate_statii' adaugă rânduri aici; funcția
+-- trigger-ului nu se schimbă. Starea securității nu face parte din istoric
+CREATE TABLE IF NOT EXISTS campuri_metadate_urmarite (
+    camp TEXT PRIMARY KEY,
+    regula TEXT CHECK (regula IN ('scadere', 'inlocuire')),
+    motiv TEXT,
+    CHECK ((regula IS NULL) = (motiv IS NULL))
+);
+
+INSERT INTO campuri_metadate_urmarite (camp, regula, motiv) VALUES
+    ('producator_procesor', NULL, NULL),
+    ('model_procesor', NULL, NULL),
+    ('nuclee', NULL, NULL),
+    ('fire_executie', NULL, NULL),
+    ('frecventa', NULL, NULL),
+    ('memorie_ram', 'scadere', 'memorie RAM scăzută'),
+    ('tip_stocare', NULL, NULL),
+    ('capacitate_stocare', NULL, NULL),
+    ('placa_de_baza', 'inlocuire', 'placă de bază schimbată'),
+    ('placa_video', NULL, NULL),
+    ('sistem_operare', NULL, NULL),
+    ('versiune_software', NULL, NULL),
+    ('arhitectura_sistem_operare', NULL, NULL),
+    ('data_instalare_sistem_operare', NULL, NULL),
+    ('licenta_sistem_operare', NULL, NULL)
+ON CONFLICT (camp) DO NOTHING;
+
+-- Câmpurile completate pentru prima dată nu sunt considerate modificări
+CREATE OR REPLACE FUNCTION metadate_statii_modificari() RETURNS trigger AS $$
+DECLARE
+    schimbare RECORD;
+    motiv TEXT;
+BEGIN
+    FOR schimbare IN
+        SELECT v.key AS camp, v.value AS vechi, n.value AS nou, c.regula, c.motiv
+        FROM jsonb_each_text(to_jsonb(OLD)) v
+        JOIN jsonb_each_text(to_jsonb(NEW)) n ON n.key = v.key
+        JOIN campuri_metadate_urmarite c ON c.camp = v.key
+        WHERE v.value IS NOT NULL
+            AND v.value IS DISTINCT FROM n.value
+        ORDER BY v.key
+    LOOP
+        motiv := NULL;
+        -- Valorile goale sau 'N/A' înseamnă de obicei că agentul nu a putut citi câmpul
+        IF schimbare.regula = 'scadere'
+            AND substring(schimbare.nou FROM '^\s*([0-9]+(?:\.[0-9]+)?)')::numeric
+                < substring(schimbare.vechi FROM '^\s*([0-9]+(?:\.[0-9]+)?)')::numeric THEN
+            motiv := schimbare.motiv;
+        ELSIF schimbare.regula = 'inlocuire'
+            AND schimbare.vechi NOT IN ('', 'N/A')
+            AND COALESCE(schimbare.nou, '') NOT IN ('', 'N/A') THEN
+            motiv := schimbare.motiv;
+        END IF;
+
+        INSERT INTO modificari_metadate (id_statie, camp, valoare_veche, valoare_noua, suspecta, motiv)
+        VALUES (NEW.id_statie, schimbare.camp, schimbare.vechi, schimbare.nou, motiv IS NOT NULL, motiv);
+
+        IF motiv IS NOT NULL THEN
+            PERFORM pg_notify('metadate_suspecte', json_build_object(
+                'id_statie', NEW.id_statie,
+                'camp', schimbare.camp,
+                'valoare_veche', schimbare.vechi,
+                'valoare_noua', schimbare.nou,
+                'motiv', motiv
+            )::text);
+        END IF;
+    END LOOP;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS metadate_statii_modificari ON metadate_statii;
+CREATE TRIGGER metadate_statii_modificari
+    AFTER UPDATE ON metadate_statii
+    FOR EACH ROW EXECUTE FUNCTION metadate_statii_modificari();
+
+-- Istoricul este doar pentru adăugare, la fel ca 'evenimente_software'
+CREATE OR REPLACE FUNCTION modificari_metadate_doar_adaugare() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'modificari_metadate permite doar adăugarea de rânduri';
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS modificari_metadate_doar_adaugare ON modificari_metadate;
+CREATE TRIGGER modificari_metadate_doar_adaugare
+    BEFORE UPDATE OR DELETE ON modificari_metadate
+    FOR EACH ROW EXECUTE FUNCTION modificari_metadate_doar_adaugare();
@@ -0,0 +1,2 @@
+DELETE FROM campuri_metadate_urmarite WHERE camp = 'versiune_kernel';
+ALTER TABLE metadate_statii DROP COLUMN IF EXISTS versiune_kernel;
@@ -0,0 +1,5 @@
+-- Versiunea kernel-ului, trimisă de agenții Linux; NULL pentru ceilalți agenți
+ALTER TABLE metadate_statii ADD COLUMN IF NOT EXISTS versiune_kernel TEXT;
+
+INSERT INTO campuri_metadate_urmarite (camp) VALUES ('versiune_kernel')
+ON CONFLICT (camp) DO NOTHING;
@@ -0,0 +1,3 @@
+DELETE FROM campuri_metadate_urmarite WHERE camp IN ('bios', 'model_stocare');
+ALTER TABLE metadate_statii DROP COLUMN IF EXISTS model_stocare;
+ALTER TABLE metadate_statii DROP COLUMN IF EXISTS bios;
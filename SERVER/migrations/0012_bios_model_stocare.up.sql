@@ -0,0 +1,7 @@
+-- Versiunea BIOS și modelul discului principal, trimise de agenți începând cu
+-- colectorul Linux; NULL pentru agenții care nu le trimit
+ALTER TABLE metadate_statii ADD COLUMN IF NOT EXISTS bios TEXT;
+ALTER TABLE metadate_statii ADD COLUMN IF NOT EXISTS model_stocare TEXT;
+
+INSERT INTO campuri_metadate_urmarite (camp) VALUES ('bios'), ('model_stocare')
+ON CONFLICT (camp) DO NOTHING;
@@ -0,0 +1,4 @@
+-- Placa de bază este considerată din nou schimbată după producător și model
+UPDATE campuri_metadate_urmarite SET regula = 'inlocuire', motiv = 'placă de bază schimbată' WHERE camp = 'placa_de_baza';
+DELETE FROM campuri_metadate_urmarite WHERE camp = 'serial_placa_de_baza';
+ALTER TABLE metadate_statii DROP COLUMN IF EXISTS serial_placa_de_baza;
@@ -0,0 +1,8 @@
+-- Seria plăcii de bază; placa este considerată schimbată doar când seria se schimbă,
+-- pentru că producătorul și modelul pot diferi între versiunile agentului sau pot
+-- rămâne aceleași la înlocuirea plăcii cu una identică
+ALTER TABLE metadate_statii ADD COLUMN IF NOT EXISTS serial_placa_de_baza TEXT;
+
+INSERT INTO campuri_metadate_urmarite (camp, regula, motiv) VALUES ('serial_placa_de_baza', 'inlocuire', 'placă de bază schimbată')
+ON CONFLICT (camp) DO UPDATE SET regula = EXCLUDED.regula, motiv = EXCLUDED.motiv;
+UPDATE campuri_metadate_urmarite SET regula = NULL, motiv = NULL WHERE camp = 'placa_de_baza';
@@ -0,0 +1 @@
+-- Forma inițială a identificatorilor nu este păstrată; cei cu minuscule rămân valizi
@@ -0,0 +1,15 @@
+-- Serverul normalizează UUID-ul DMI la minuscule; identificatorii salvați înainte
+-- cu majuscule sunt aduși la aceeași formă, pentru ca stațiile să nu fie duplicate
+-- Dacă forma cu minuscule există deja, rândul vechi rămâne neschimbat
+UPDATE statii_de_lucru s
+SET identificator_statie = lower(btrim(s.identificator_statie))
+WHERE s.identificator_statie LIKE 'product-uuid:%'
+    AND s.identificator_statie <> lower(btrim(s.identificator_statie))
+    AND NOT EXISTS (
+        SELECT 1 FROM statii_de_lucru d
+        WHERE d.identificator_statie = lower(btrim(s.identificator_statie))
+    )
+    AND s.id_statie = (
+        SELECT min(x.id_statie) FROM statii_de_lucru x
+        WHERE lower(btrim(x.identificator_statie)) = lower(btrim(s.identificator_statie))
+    );
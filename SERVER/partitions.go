@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Partițiile tabelului 'metrici_statii'
+//
+// Tabelul este partiționat după momentul colectării (migrarea 0007). Serverul
+// creează din timp partițiile pentru următoarele perioade (zile sau săptămâni)
+// și aplică retenția eșantioanelor brute ștergând partițiile întregi, după ce
+// toate eșantioanele din ele au fost agregate
+// Eșantioanele din afara partițiilor ajung în 'metrici_statii_implicit'
+
+// Perioadele de partiționare acceptate
+const (
+	partitionDay  = "zi"
+	partitionWeek = "saptamana"
+)
+
+// Partiția implicită, pentru eșantioanele din afara celorlalte partiții
+const defaultMetricsPartition = "metrici_statii_implicit"
+
+// Formatul limitelor partițiilor (coloana 'timestamp' nu are fus orar)
+const partitionBoundLayout = "2006-01-02 15:04:05"
+
+// O partiție a tabelului, cu limitele ei; from este zero pentru MINVALUE
+type metricsPartition struct {
+	name string
+	from time.Time
+	to   time.Time
+}
+
+// Limitele unei partiții, așa cum sunt afișate de pg_get_expr
+var partitionBoundPattern = regexp.MustCompile(`FROM \((.+)\) TO \((.+)\)`)
+
+// Funcție care interpretează o limită a unei partiții
+func parsePartitionBound(bound string) (time.Time, error) {
+	if bound == "MINVALUE" {
+		return time.Time{}, nil
+	}
+	if len(bound) < 2 || bound[0] != '\'' || bound[len(bound)-1] != '\'' {
+		return time.Time{}, fmt.Errorf("limita '%s' nu este un moment", bound)
+	}
+	return time.Parse(partitionBoundLayout, bound[1:len(bound)-1])
+}
+
+// Funcție care întoarce începutul perioadei care conține momentul dat
+// Săptămânile încep lunea
+func partitionStart(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if period == partitionWeek {
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+// Funcție care întoarce începutul perioadei următoare după momentul dat
+func nextPartitionBound(t time.Time, period string) time.Time {
+	if period == partitionWeek {
+		return partitionStart(t, period).AddDate(0, 0, 7)
+	}
+	return partitionStart(t, period).AddDate(0, 0, 1)
+}
+
+// Funcție care întoarce partițiile pe intervale ale tabelului, fără cea implicită
+func listMetricsPartitions(ctx context.Context, db *sql.DB) ([]metricsPartition, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'metrici_statii'::regclass
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("eroare la citirea partițiilor: %w", err)
+	}
+	defer rows.Close()
+
+	var partitions []metricsPartition
+	for rows.Next() {
+		var name, bound string
+		err = rows.Scan(&name, &bound)
+		if err != nil {
+			return nil, fmt.Errorf("eroare la citirea partițiilor: %w", err)
+		}
+		match := partitionBoundPattern.FindStringSubmatch(bound)
+		if match == nil {
+			continue
+		}
+		p := metricsPartition{name: name}
+		p.from, err = parsePartitionBound(match[1])
+		if err == nil {
+			p.to, err = parsePartitionBound(match[2])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("eroare la citirea limitelor partiției %s: %w", name, err)
+		}
+		partitions = append(partitions, p)
+	}
+	return partitions, rows.Err()
+}
+
+// Funcție care creează o partiție; eșantioanele din intervalul ei aflate deja
+// în partiția implicită sunt mutate în partiția nouă
+func createMetricsPartition(ctx context.Context, db *sql.DB, p metricsPartition) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("eroare la începerea tranzacției: %w", err)
+	}
+	defer tx.Rollback()
+
+	from, to := p.from.Format(partitionBoundLayout), p.to.Format(partitionBoundLayout)
+	statements := []string{
+		fmt.Sprintf(`CREATE TEMP TABLE metrici_mutate ON COMMIT DROP AS
+			SELECT * FROM %s WHERE timestamp >= '%s' AND timestamp < '%s'`, defaultMetricsPartition, from, to),
+		fmt.Sprintf(`DELETE FROM %s WHERE timestamp >= '%s' AND timestamp < '%s'`, defaultMetricsPartition, from, to),
+		fmt.Sprintf(`CREATE TABLE %s PARTITION OF metrici_statii FOR VALUES FROM ('%s') TO ('%s')`, p.name, from, to),
+		`INSERT INTO metrici_statii SELECT * FROM metrici_mutate`,
+	}
+	for _, statement := range statements {
+		_, err = tx.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("eroare la crearea partiției %s: %w", p.name, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("eroare la crearea partiției %s: %w", p.name, err)
+	}
+	fmt.Printf("Partiție nouă: %s [%s, %s)\n", p.name, from, to)
+	return nil
+}
+
+// Funcție care creează partițiile lipsă pentru perioada curentă și următoarele 'ahead' perioade
+func ensureMetricsPartitions(ctx context.Context, db *sql.DB, period string, ahead int) error {
+	partitions, err := listMetricsPartitions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var now time.Time
+	err = db.QueryRowContext(ctx, "SELECT LOCALTIMESTAMP").Scan(&now)
+	if err != nil {
+		return fmt.Errorf("eroare la citirea orei bazei de date: %w", err)
+	}
+
+	// Partițiile noi încep cu perioada curentă sau după ultima partiție existentă;
+	// un gol lăsat de o oprire mai lungă a serverului rămâne în partiția implicită
+	next := partitionStart(now, period)
+	for _, p := range partitions {
+		if p.to.After(next) {
+			next = p.to
+		}
+	}
+
+	until := partitionStart(now, period)
+	for i := 0; i < ahead; i++ {
+		until = nextPartitionBound(until, period)
+	}
+	for !next.After(until) {
+		to := nextPartitionBound(next, period)
+		err = createMetricsPartition(ctx, db, metricsPartition{
+			name: "metrici_statii_p" + next.Format("20060102"),
+			from: next,
+			to:   to,
+		})
+		if err != nil {
+			return err
+		}
+		next = to
+	}
+	return nil
+}
+
+// Funcție care șterge partițiile mai vechi decât retenția, dacă toate
+// eșantioanele din ele au fost agregate; din partiția implicită sunt
+// șterse doar rândurile vechi
+func dropExpiredMetricsPartitions(ctx context.Context, db *sql.DB, retention time.Duration) error {
+	if retention <= 0 {
+		return nil
+	}
+	partitions, err := listMetricsPartitions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var now, rolledUpTo time.Time
+	err = db.QueryRowContext(ctx, `
+		SELECT LOCALTIMESTAMP, procesat_pana_la FROM stare_agregare_metrici WHERE nume = 'metrici_statii'
+	`).Scan(&now, &rolledUpTo)
+	if err != nil {
+		return fmt.Errorf("eroare la citirea stării agregării: %w", err)
+	}
+	limit := now.Add(-retention)
+
+	for _, p := range partitions {
+		if p.to.After(limit) {
+			continue
+		}
+		var pending bool
+		err = db.QueryRowContext(ctx, fmt.Sprintf(`
+			SELECT EXISTS (SELECT 1 FROM %s WHERE primit_la > $1)
+		`, p.name), rolledUpTo).Scan(&pending)
+		if err != nil {
+			return fmt.Errorf("eroare la verificarea partiției %s: %w", p.name, err)
+		}
+		if pending {
+			continue
+		}
+
+		_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE metrici_statii DETACH PARTITION %s", p.name))
+		if err != nil {
+			return fmt.Errorf("eroare la detașarea partiției %s: %w", p.name, err)
+		}
+		_, err = db.ExecContext(ctx, fmt.Sprintf("DROP TABLE %s", p.name))
+		if err != nil {
+			return fmt.Errorf("eroare la ștergerea partiției %s: %w", p.name, err)
+		}
+		fmt.Printf("Retenție: partiția %s a fost ștearsă\n", p.name)
+	}
+
+	result, err := db.ExecContext(ctx, fmt.Sprintf(`
+		DELETE FROM %s WHERE timestamp < $1 AND primit_la <= $2
+	`, defaultMetricsPartition), limit, rolledUpTo)
+	if err != nil {
+		return fmt.Errorf("eroare la ștergerea metricilor vechi: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		fmt.Printf("Retenție: %d eșantioane șterse din %s\n", n, defaultMetricsPartition)
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Versiunile formatului datelor trimise de agent
+const (
+	// Formatul vechi: secțiunile și datele live direct la rădăcină
+	schemaV1 = 1
+	// Plicul cu versiune, tip, identitatea stației și secțiuni
+	schemaV2 = 2
+)
+
+// Secțiunile trimise de agent; lipsesc cele care nu au fost colectate
+type Sections struct {
+	SistemDeOperare *OSInfo         `json:"sistem_de_operare"`
+	Hardware        *HardwareInfo   `json:"hardware"`
+	Software        *SoftwareInfo   `json:"software"`
+	Securitate      *string         `json:"securitate"`
+	Utilizator      *UserInfo       `json:"utilizator"`
+	Live            *LiveSystemInfo `json:"live"`
+}
+
+// Funcție care arată dacă secțiunea cu numele dat a fost trimisă
+func (s *Sections) has(name string) bool {
+	switch name {
+	case "sistem_de_operare":
+		return s.SistemDeOperare != nil
+	case "hardware":
+		return s.Hardware != nil
+	case "software":
+		return s.Software != nil
+	case "securitate":
+		return s.Securitate != nil
+	case "utilizator":
+		return s.Utilizator != nil
+	case "live":
+		return s.Live != nil
+	}
+	return false
+}
+
+// Secțiunile de inventar, în ordinea în care sunt salvate
+var inventorySections = []string{"sistem_de_operare", "hardware", "software", "securitate", "utilizator"}
+
+// Datele primite de la agent, indiferent de versiunea formatului
+type Payload struct {
+	VersiuneSchema int               `json:"versiune_schema"`
+	VersiuneAgent  string            `json:"versiune_agent"`
+	Tip            string            `json:"tip"`
+	Statie         *StationIdentity  `json:"statie"`
+	ColectatLa     *time.Time        `json:"colectat_la"`
+	Secventa       int64             `json:"secventa"`
+	Hashuri        map[string]string `json:"hashuri"`
+	Sectiuni       Sections          `json:"sectiuni"`
+}
+
+// Formatul vechi (versiunea 1): secțiunile și datele live direct la rădăcină
+type payloadV1 struct {
+	Tip               string            `json:"tip"`
+	Statie            *StationIdentity  `json:"statie"`
+	ColectatLa        *time.Time        `json:"colectat_la"`
+	Secventa          int64             `json:"secventa"`
+	Hashuri           map[string]string `json:"hashuri"`
+	SistemDeOperare   *OSInfo           `json:"sistem_de_operare"`
+	Hardware          *HardwareInfo     `json:"hardware"`
+	Software          *SoftwareInfo     `json:"software"`
+	Securitate        *string           `json:"securitate"`
+	Utilizator        *UserInfo         `json:"utilizator"`
+	UtilizareCPU      *float64          `json:"utilizare_cpu"`
+	UtilizareRAM      *float64          `json:"utilizare_ram"`
+	TraficTrimis      *uint64           `json:"trafic_retea_bytes_trimisi"`
+	TraficReceptionat *uint64           `json:"trafic_retea_bytes_primiti"`
+}
+
+// Funcție care convertește datele din formatul vechi
+func (v1 *payloadV1) payload() *Payload {
+	p := &Payload{
+		VersiuneSchema: schemaV1,
+		Tip:            v1.Tip,
+		Statie:         v1.Statie,
+		ColectatLa:     v1.ColectatLa,
+		Secventa:       v1.Secventa,
+		Hashuri:        v1.Hashuri,
+		Sectiuni: Sections{
+			SistemDeOperare: v1.SistemDeOperare,
+			Hardware:        v1.Hardware,
+			Software:        v1.Software,
+			Securitate:      v1.Securitate,
+			Utilizator:      v1.Utilizator,
+		},
+	}
+	if v1.UtilizareCPU != nil || v1.UtilizareRAM != nil || v1.TraficTrimis != nil || v1.TraficReceptionat != nil {
+		live := &LiveSystemInfo{}
+		if v1.UtilizareCPU != nil {
+			live.UtilizareCPU = *v1.UtilizareCPU
+		}
+		if v1.UtilizareRAM != nil {
+			live.UtilizareRAM = *v1.UtilizareRAM
+		}
+		if v1.TraficTrimis != nil {
+			live.TraficTrimis = *v1.TraficTrimis
+		}
+		if v1.TraficReceptionat != nil {
+			live.TraficReceptionat = *v1.TraficReceptionat
+		}
+		p.Sectiuni.Live = live
+	}
+	return p
+}
+
+// Eroare pentru un câmp invalid din datele primite
+type fieldError struct {
+	Camp  string `json:"camp"`
+	Mesaj string `json:"mesaj"`
+}
+
+// Eroare pentru date invalide; conține toate câmpurile invalide
+type validationError struct {
+	erori []fieldError
+}
+
+func (e *validationError) Error() string {
+	messages := make([]string, 0, len(e.erori))
+	for _, fe := range e.erori {
+		messages = append(messages, fmt.Sprintf("%s: %s", fe.Camp, fe.Mesaj))
+	}
+	return "date invalide: " + strings.Join(messages, "; ")
+}
+
+// Funcție care adaugă o eroare pentru câmpul dat
+func (e *validationError) add(camp, format string, args ...interface{}) {
+	e.erori = append(e.erori, fieldError{Camp: camp, Mesaj: fmt.Sprintf(format, args...)})
+}
+
+// Structura pentru răspunsul trimis agentului când datele sunt invalide
+type errorResponse struct {
+	Eroare string       `json:"eroare"`
+	Erori  []fieldError `json:"erori,omitempty"`
+}
+
+// Funcție care trimite o eroare 400; erorile de validare sunt trimise pe câmpuri
+func writeBadRequest(w http.ResponseWriter, message string, err error) {
+	response := errorResponse{Eroare: message}
+	var invalid *validationError
+	if errors.As(err, &invalid) {
+		response.Erori = invalid.erori
+	} else {
+		response.Erori = []fieldError{{Mesaj: err.Error()}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(response)
+}
+
+// Funcție care decodează și validează datele primite de la agent
+// Datele fără 'versiune_schema' sunt în formatul vechi (versiunea 1); dacă nu
+// conțin identitatea stației, stația este identificată după adresa cererii
+func decodePayload(data []byte, remoteAddr string) (*Payload, error) {
+	var header struct {
+		VersiuneSchema *int `json:"versiune_schema"`
+	}
+	err := json.Unmarshal(data, &header)
+	if err != nil {
+		return nil, decodeError(err)
+	}
+
+	var p *Payload
+	switch {
+	case header.VersiuneSchema == nil:
+		var v1 payloadV1
+		err = json.Unmarshal(data, &v1)
+		if err != nil {
+			return nil, decodeError(err)
+		}
+		p = v1.payload()
+		if p.Statie == nil {
+			p.Statie = remoteStationIdentity(remoteAddr)
+		}
+	case *header.VersiuneSchema == schemaV2:
+		// Plicul nou este decodat strict: câmpurile necunoscute sunt respinse
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		p = &Payload{}
+		err = decoder.Decode(p)
+		if err != nil {
+			return nil, decodeError(err)
+		}
+	default:
+		invalid := &validationError{}
+		invalid.add("versiune_schema", "versiunea %d nu este suportată (sunt suportate versiunile %d și %d)", *header.VersiuneSchema, schemaV1, schemaV2)
+		return nil, invalid
+	}
+
+	err = p.validate()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Funcție care transformă erorile de decodare JSON în erori pe câmpuri
+func decodeError(err error) error {
+	invalid := &validationError{}
+
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		invalid.add(typeErr.Field, "valoarea %s nu este de tipul %s", typeErr.Value, typeErr.Type)
+	case errors.As(err, &syntaxErr):
+		invalid.add("", "JSON invalid la poziția %d: %v", syntaxErr.Offset, syntaxErr)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		invalid.add(field, "câmpul nu este cunoscut")
+	default:
+		invalid.add("", "%v", err)
+	}
+	return invalid
+}
+
+// Funcție care verifică datele decodate și întoarce toate câmpurile invalide
+func (p *Payload) validate() error {
+	invalid := &validationError{}
+
+	// În formatul vechi secțiunile sunt direct la rădăcină
+	prefix := "sectiuni."
+	if p.VersiuneSchema == schemaV1 {
+		prefix = ""
+	}
+
+	if p.Statie == nil {
+		invalid.add("statie", "identitatea stației lipsește")
+	} else if err := p.Statie.normalize(); err != nil {
+		invalid.add("statie", "%v", err)
+	}
+	if p.Secventa < 0 {
+		invalid.add("secventa", "numărul de secvență nu poate fi negativ")
+	}
+
+	// Secțiunile obligatorii, în funcție de tipul datelor
+	var required []string
+	switch p.Tip {
+	case tipLive:
+		required = []string{"live"}
+	case tipInventar:
+		if p.Hashuri == nil {
+			required = []string{"sistem_de_operare", "hardware", "software", "securitate"}
+		}
+	case "":
+		// Datele fără tip, trimise de agenții mai vechi, conțin ambele părți
+		if p.VersiuneSchema != schemaV1 {
+			invalid.add("tip", "tipul datelor lipsește")
+		}
+		required = []string{"live", "sistem_de_operare", "hardware", "software", "securitate"}
+	default:
+		invalid.add("tip", "tipul '%s' nu este cunoscut (live sau inventar)", p.Tip)
+	}
+	for _, section := range required {
+		if !p.Sectiuni.has(section) {
+			name := prefix + section
+			if section == "live" && p.VersiuneSchema == schemaV1 {
+				name = "utilizare_cpu"
+			}
+			invalid.add(name, "secțiunea lipsește")
+		}
+	}
+
+	// Fiecare secțiune de inventar trimisă pe secțiuni are nevoie de hash
+	if p.Hashuri != nil {
+		sections := make([]string, 0, len(p.Hashuri))
+		for section := range p.Hashuri {
+			sections = append(sections, section)
+		}
+		sort.Strings(sections)
+		for _, section := range sections {
+			hash := p.Hashuri[section]
+			if !isInventorySection(section) {
+				invalid.add("hashuri."+section, "secțiunea nu este cunoscută")
+			} else if hash == "" {
+				invalid.add("hashuri."+section, "hash-ul lipsește")
+			}
+		}
+		for _, section := range inventorySections {
+			if p.Sectiuni.has(section) && p.Hashuri[section] == "" {
+				invalid.add("hashuri."+section, "secțiunea a fost trimisă fără hash")
+			}
+		}
+	}
+
+	if live := p.Sectiuni.Live; live != nil {
+		livePrefix := prefix + "live."
+		if p.VersiuneSchema == schemaV1 {
+			livePrefix = ""
+		}
+		if live.UtilizareCPU < 0 || live.UtilizareCPU > 100 {
+			invalid.add(livePrefix+"utilizare_cpu", "valoarea %.2f nu este un procent", live.UtilizareCPU)
+		}
+		if live.UtilizareRAM < 0 || live.UtilizareRAM > 100 {
+			invalid.add(livePrefix+"utilizare_ram", "valoarea %.2f nu este un procent", live.UtilizareRAM)
+		}
+	}
+
+	if hardware := p.Sectiuni.Hardware; hardware != nil {
+		if hardware.Nuclee < 0 {
+			invalid.add(prefix+"hardware.nuclee", "numărul de nuclee nu poate fi negativ")
+		}
+		if hardware.FireExecutie < 0 {
+			invalid.add(prefix+"hardware.fire_executie", "numărul de fire de execuție nu poate fi negativ")
+		}
+	}
+
+	if software := p.Sectiuni.Software; software != nil {
+		for i, program := range software.ProgrameInstalate {
+			if strings.TrimSpace(program.Nume) == "" {
+				invalid.add(fmt.Sprintf("%ssoftware.programe_instalate[%d].nume", prefix, i), "numele programului lipsește")
+			}
+		}
+	}
+
+	if len(invalid.erori) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// Funcție care arată dacă numele dat este al unei secțiuni de inventar
+func isInventorySection(name string) bool {
+	for _, section := range inventorySections {
+		if section == name {
+			return true
+		}
+	}
+	return false
+}
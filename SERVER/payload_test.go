@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Un inventar complet în formatul vechi, trimis de agenții fără versiune_schema
+const payloadV1Inventory = `{
+	"sistem_de_operare": {"nume": "Windows 10 Pro", "versiune": "10.0.19045"},
+	"hardware": {"procesor": "Intel Core i5", "nuclee": 4, "fire_executie": 8, "placa_de_baza": "ASUS PRIME B450"},
+	"software": {"programe_instalate": [{"nume": "7-Zip", "versiune": "19.00"}]},
+	"securitate": "Windows Defender",
+	"utilizare_cpu": 12.5,
+	"utilizare_ram": 40
+}`
+
+// Funcție care întoarce câmpurile invalide dintr-o eroare de validare
+func invalidFields(t *testing.T, err error) []string {
+	t.Helper()
+	var invalid *validationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("eroarea %v nu este o eroare de validare", err)
+	}
+	fields := make([]string, 0, len(invalid.erori))
+	for _, fe := range invalid.erori {
+		fields = append(fields, fe.Camp)
+	}
+	return fields
+}
+
+func TestDecodePayload(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string
+		check      func(t *testing.T, p *Payload)
+	}{
+		{
+			name: "v2 live",
+			body: `{"versiune_schema": 2, "tip": "live", "statie": {"id": "A1"}, "secventa": 7,
+				"sectiuni": {"live": {"utilizare_cpu": 5, "utilizare_ram": 60}}}`,
+			check: func(t *testing.T, p *Payload) {
+				if p.Sectiuni.Live == nil || p.Sectiuni.Live.UtilizareRAM != 60 || p.Secventa != 7 {
+					t.Errorf("payload = %+v", p)
+				}
+			},
+		},
+		{
+			name: "v2 inventar pe secțiuni",
+			body: `{"versiune_schema": 2, "tip": "inventar", "statie": {"product_uuid": "ABC"},
+				"hashuri": {"hardware": "h1"}, "sectiuni": {"hardware": {"placa_de_baza": "MSI"}}}`,
+			check: func(t *testing.T, p *Payload) {
+				if p.Statie.ProductUUID != "abc" || p.Sectiuni.Hardware.PlacaDeBaza != "MSI" {
+					t.Errorf("payload = %+v", p)
+				}
+			},
+		},
+		{
+			name:       "v2 câmp necunoscut",
+			body:       `{"versiune_schema": 2, "tip": "live", "statie": {"id": "A1"}, "sectiuni": {"live": {}}, "extra": 1}`,
+			wantFields: []string{"extra"},
+		},
+		{
+			name:       "v2 câmp necunoscut într-o secțiune",
+			body:       `{"versiune_schema": 2, "tip": "live", "statie": {"id": "A1"}, "sectiuni": {"live": {"temperatura": 50}}}`,
+			wantFields: []string{"temperatura"},
+		},
+		{
+			name:       "v2 tip greșit",
+			body:       `{"versiune_schema": 2, "tip": "live", "statie": {"id": "A1"}, "secventa": "7", "sectiuni": {"live": {}}}`,
+			wantFields: []string{"secventa"},
+		},
+		{
+			name:       "v2 placa_de_baza număr",
+			body:       `{"versiune_schema": 2, "tip": "inventar", "statie": {"id": "A1"}, "hashuri": {"hardware": "h1"}, "sectiuni": {"hardware": {"placa_de_baza": 42}}}`,
+			wantFields: []string{"sectiuni.hardware.placa_de_baza"},
+		},
+		{
+			name:       "v2 programe_instalate obiect",
+			body:       `{"versiune_schema": 2, "tip": "inventar", "statie": {"id": "A1"}, "hashuri": {"software": "h1"}, "sectiuni": {"software": {"programe_instalate": {"nume": "7-Zip"}}}}`,
+			wantFields: []string{"sectiuni.software.programe_instalate"},
+		},
+		{
+			name: "v2 valori invalide",
+			body: `{"versiune_schema": 2, "tip": "inventar", "statie": {"id": " "}, "secventa": -1,
+				"hashuri": {"hardware": "", "retea": "h"}, "sectiuni": {"hardware": {"nuclee": -2}, "software": {"programe_instalate": [{"nume": " "}]}}}`,
+			wantFields: []string{"statie", "secventa", "hashuri.hardware", "hashuri.retea", "hashuri.hardware", "hashuri.software",
+				"sectiuni.hardware.nuclee", "sectiuni.software.programe_instalate[0].nume"},
+		},
+		{
+			name:       "v2 fără tip",
+			body:       `{"versiune_schema": 2, "statie": {"id": "A1"}}`,
+			wantFields: []string{"tip", "sectiuni.live", "sectiuni.sistem_de_operare", "sectiuni.hardware", "sectiuni.software", "sectiuni.securitate"},
+		},
+		{
+			name:       "versiune necunoscută",
+			body:       `{"versiune_schema": 3}`,
+			wantFields: []string{"versiune_schema"},
+		},
+		{
+			name:       "JSON invalid",
+			body:       `{"versiune_schema": 2,`,
+			wantFields: []string{""},
+		},
+		{
+			name: "v1 fără identitate",
+			body: payloadV1Inventory,
+			check: func(t *testing.T, p *Payload) {
+				if p.VersiuneSchema != schemaV1 || p.Statie.key() != "adresa:10.0.0.5" {
+					t.Errorf("payload = %+v, statie = %+v", p, p.Statie)
+				}
+				if p.Sectiuni.Live == nil || p.Sectiuni.Live.UtilizareCPU != 12.5 {
+					t.Errorf("live = %+v", p.Sectiuni.Live)
+				}
+				if len(p.Sectiuni.Software.ProgrameInstalate) != 1 {
+					t.Errorf("software = %+v", p.Sectiuni.Software)
+				}
+			},
+		},
+		{
+			name: "v1 acceptă câmpuri necunoscute",
+			body: `{"tip": "live", "statie": {"hostname": "PC-1"}, "utilizare_cpu": 1, "temperatura": 50}`,
+			check: func(t *testing.T, p *Payload) {
+				if p.Statie.key() != "hostname:PC-1" {
+					t.Errorf("key = %q", p.Statie.key())
+				}
+			},
+		},
+		{
+			name:       "v1 secțiuni lipsă",
+			body:       `{"utilizare_cpu": 150}`,
+			wantFields: []string{"sistem_de_operare", "hardware", "software", "securitate", "utilizare_cpu"},
+		},
+		{
+			name:       "v1 placa_de_baza număr",
+			body:       `{"hardware": {"placa_de_baza": 42}}`,
+			wantFields: []string{"hardware.placa_de_baza"},
+		},
+		{
+			name:       "v1 programe_instalate obiect",
+			body:       `{"software": {"programe_instalate": {"nume": "7-Zip"}}}`,
+			wantFields: []string{"software.programe_instalate"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := decodePayload([]byte(tt.body), "10.0.0.5:40000")
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Fatalf("decodePayload: %v", err)
+				}
+				tt.check(t, p)
+				return
+			}
+			if err == nil {
+				t.Fatalf("decodePayload: eroare așteptată pentru %v", tt.wantFields)
+			}
+			fields := invalidFields(t, err)
+			if len(fields) != len(tt.wantFields) {
+				t.Fatalf("câmpuri invalide %q, așteptat %q (%v)", fields, tt.wantFields, err)
+			}
+			for i := range fields {
+				if fields[i] != tt.wantFields[i] {
+					t.Errorf("câmpul %d = %q, așteptat %q (%v)", i, fields[i], tt.wantFields[i], err)
+				}
+			}
+		})
+	}
+}
+
+// Răspunsul 400 conține mesajul și câmpurile invalide
+func TestWriteBadRequest(t *testing.T) {
+	invalid := &validationError{}
+	invalid.add("secventa", "numărul de secvență nu poate fi negativ")
+	invalid.add("tip", "tipul datelor lipsește")
+
+	tests := []struct {
+		name string
+		err  error
+		want errorResponse
+	}{
+		{
+			name: "validare",
+			err:  invalid,
+			want: errorResponse{Eroare: "Date invalide", Erori: invalid.erori},
+		},
+		{
+			name: "altă eroare",
+			err:  errors.New("corpul cererii este prea mare"),
+			want: errorResponse{Eroare: "Date invalide", Erori: []fieldError{{Mesaj: "corpul cererii este prea mare"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeBadRequest(rec, "Date invalide", tt.err)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("cod = %d, așteptat %d", rec.Code, http.StatusBadRequest)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			var got errorResponse
+			err := json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("răspunsul %q nu este JSON: %v", rec.Body.String(), err)
+			}
+			if got.Eroare != tt.want.Eroare || len(got.Erori) != len(tt.want.Erori) {
+				t.Fatalf("răspuns = %+v, așteptat %+v", got, tt.want)
+			}
+			for i := range got.Erori {
+				if got.Erori[i] != tt.want.Erori[i] {
+					t.Errorf("eroarea %d = %+v, așteptat %+v", i, got.Erori[i], tt.want.Erori[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Structura pentru identitatea unei stații, așa cum este trimisă de agent
+type StationIdentity struct {
+	ID          string `json:"id"`
+	MachineID   string `json:"machine_id"`
+	ProductUUID string `json:"product_uuid"`
+	MAC         string `json:"mac"`
+	Hostname    string `json:"hostname"`
+	// Adresa de la care a fost trimisă cererea; folosită doar pentru agenții
+	// vechi, care nu trimit identitatea stației
+	Adresa string `json:"-"`
+}
+
+// Funcție care întoarce cheia după care este identificată stația:
+// ID-ul generat de agent, apoi UUID-ul DMI, machine-id, hostname și adresa
+func (identity StationIdentity) key() string {
+	switch {
+	case identity.ID != "":
+		return "id:" + identity.ID
+	case identity.ProductUUID != "":
+		return "product-uuid:" + identity.ProductUUID
+	case identity.MachineID != "":
+		return "machine-id:" + identity.MachineID
+	case identity.Hostname != "":
+		return "hostname:" + identity.Hostname
+	case identity.Adresa != "":
+		return "adresa:" + identity.Adresa
+	}
+	return ""
+}
+
+// Funcție care întoarce identitatea unui agent vechi, după adresa cererii
+// Agenții din spatele aceluiași NAT sau proxy ajung pe aceeași stație
+func remoteStationIdentity(remoteAddr string) *StationIdentity {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return &StationIdentity{Adresa: host}
+}
+
+// Funcție care întoarce numele afișat al stației
+func (identity StationIdentity) name() string {
+	if identity.Hostname != "" {
+		return identity.Hostname
+	}
+	return identity.key()
+}
+
+// Funcție care normalizează identitatea trimisă de agent și verifică
+// că stația are cel puțin un identificator
+func (identity *StationIdentity) normalize() error {
+	identity.ID = strings.TrimSpace(identity.ID)
+	identity.MachineID = strings.TrimSpace(identity.MachineID)
+	identity.Hostname = strings.TrimSpace(identity.Hostname)
+	// Windows și Linux afișează UUID-ul DMI și adresa MAC cu majuscule, respectiv minuscule
+	identity.ProductUUID = strings.ToLower(strings.TrimSpace(identity.ProductUUID))
+	identity.MAC = strings.ToLower(strings.TrimSpace(identity.MAC))
+
+	if identity.key() == "" {
+		return fmt.Errorf("stația nu are niciun identificator (id, product_uuid, machine_id sau hostname)")
+	}
+	return nil
+}
+
+// Intrare din memoria registrului: ID-ul stației și ultimul nume cunoscut
+type cachedStation struct {
+	id   int
+	name string
+}
+
+// Registrul stațiilor: asociază identitatea trimisă de agent cu rândul din
+// 'statii_de_lucru' și păstrează asocierea în memorie
+type stationRegistry struct {
+	db           *sql.DB
+	defaultOwner int // persoana căreia îi sunt atribuite stațiile noi
+	mu           sync.RWMutex
+	cache        map[string]cachedStation
+}
+
+// Funcție pentru a crea registrul stațiilor
+func newStationRegistry(db *sql.DB, defaultOwner int) *stationRegistry {
+	return &stationRegistry{
+		db:           db,
+		defaultOwner: defaultOwner,
+		cache:        make(map[string]cachedStation),
+	}
+}
+
+// Funcție care verifică la pornire că persoana implicită există, pentru ca
+// înregistrarea primei stații noi să nu eșueze abia la prima cerere
+func (registry *stationRegistry) checkDefaultOwner() error {
+	var exists bool
+	err := registry.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM persoane WHERE id_persoana = $1)`, registry.defaultOwner).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("eroare la verificarea persoanei implicite: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("persoana implicită %d nu există în 'persoane' (statii.persoana_implicita)", registry.defaultOwner)
+	}
+	return nil
+}
+
+// Funcție pentru a obține ID-ul stației din baza de date
+// sau pentru a crea o intrare nouă dacă nu există
+func (registry *stationRegistry) stationID(identity StationIdentity) (int, error) {
+	key := identity.key()
+
+	registry.mu.RLock()
+	cached, ok := registry.cache[key]
+	registry.mu.RUnlock()
+	if ok && cached.name == identity.name() {
+		return cached.id, nil
+	}
+
+	// Stația este nouă sau a fost redenumită: numele este actualizat, ID-ul rămâne
+	// Stațiile noi sunt atribuite persoanei implicite din configurație
+	var idStatie int
+	err := registry.db.QueryRow(`
+		INSERT INTO statii_de_lucru (nume_statie, id_persoana, identificator_statie)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (identificator_statie) DO UPDATE SET nume_statie = EXCLUDED.nume_statie
+		RETURNING id_statie
+	`, identity.name(), registry.defaultOwner, key).Scan(&idStatie)
+	if err != nil {
+		return 0, fmt.Errorf("eroare la obținerea/crearea intrării stației de lucru: %w", err)
+	}
+
+	registry.mu.Lock()
+	registry.cache[key] = cachedStation{id: idStatie, name: identity.name()}
+	registry.mu.Unlock()
+
+	return idStatie, nil
+}
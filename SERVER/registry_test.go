@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+// Aceeași mașină raportată de Windows (majuscule) și de Linux (minuscule)
+// trebuie să aibă aceeași identitate
+func TestStationIdentityNormalize(t *testing.T) {
+	identity := StationIdentity{
+		ProductUUID: " 4C4C4544-0042-3510-8052-B4C04F4B4E32\r\n",
+		MAC:         "00:1A:2B:3C:4D:5E ",
+		Hostname:    " PC-CONTABILITATE ",
+	}
+	err := identity.normalize()
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	want := StationIdentity{
+		ProductUUID: "4c4c4544-0042-3510-8052-b4c04f4b4e32",
+		MAC:         "00:1a:2b:3c:4d:5e",
+		Hostname:    "PC-CONTABILITATE",
+	}
+	if identity != want {
+		t.Errorf("normalize:\n got  %+v\n want %+v", identity, want)
+	}
+	if key := identity.key(); key != "product-uuid:4c4c4544-0042-3510-8052-b4c04f4b4e32" {
+		t.Errorf("key = %q", key)
+	}
+
+	// Agenții vechi nu trimit identitatea; stația este cea a adresei cererii
+	legacy := remoteStationIdentity("192.168.10.25:51234")
+	if err := legacy.normalize(); err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if key := legacy.key(); key != "adresa:192.168.10.25" {
+		t.Errorf("key = %q", key)
+	}
+
+	empty := StationIdentity{ProductUUID: "  ", MAC: "00:1a:2b:3c:4d:5e"}
+	if err := empty.normalize(); err == nil {
+		t.Error("normalize: eroare așteptată pentru o stație fără identificator")
+	}
+}
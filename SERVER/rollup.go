@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Agregarea periodică a metricilor și retenția lor
+//
+// Eșantioanele brute din 'metrici_statii' sunt agregate pe minut, iar agregatele
+// pe minut pe oră și pe zi (min, medie, max, număr de eșantioane). Intervalele
+// sunt alese după momentul primirii, nu al colectării: un eșantion sosit târziu
+// din coada agentului recalculează intervalele din care face parte
+// Datele mai vechi decât retenția fiecărui tabel sunt șterse după agregare
+
+// Rezoluțiile agregatelor, de la cea mai fină la cea mai grosieră
+type metricRollup struct {
+	table  string
+	unit   string // unitatea pentru date_trunc
+	bucket time.Duration
+}
+
+var metricRollups = []metricRollup{
+	{table: "metrici_statii_1m", unit: "minute", bucket: time.Minute},
+	{table: "metrici_statii_1h", unit: "hour", bucket: time.Hour},
+	{table: "metrici_statii_1d", unit: "day", bucket: 24 * time.Hour},
+}
+
+// Eșantioanele primite în ultimul minut nu sunt încă agregate: tranzacțiile
+// care le salvează pot fi încă deschise
+const rollupDelay = time.Minute
+
+// Retenția metricilor; 0 înseamnă că datele nu sunt șterse
+type metricsRetention struct {
+	raw    time.Duration
+	minute time.Duration
+	hour   time.Duration
+	day    time.Duration
+}
+
+// Funcție care întoarce vârsta maximă a unui eșantion care poate fi încă agregat corect
+// Un eșantion sosit târziu recalculează intervalele din care face parte din rezoluția
+// mai fină, care trebuie să fie încă completă: eșantioanele brute ale minutului,
+// agregatele pe minut ale orei și agregatele pe oră ale zilei. Altfel agregatul corect
+// ar fi înlocuit cu unul calculat din datele rămase
+// interval este intervalul la care rulează agregarea
+func (r metricsRetention) maxSampleAge(interval time.Duration) time.Duration {
+	// Timpul până când eșantionul este agregat
+	lag := rollupDelay + interval
+	sources := []struct {
+		retention time.Duration
+		bucket    time.Duration // intervalul recalculat din această sursă
+	}{
+		{r.raw, time.Minute},
+		{r.minute, time.Hour},
+		{r.hour, 24 * time.Hour},
+	}
+
+	limit := maxSampleAge
+	for _, source := range sources {
+		if source.retention > 0 && source.retention-source.bucket-lag < limit {
+			limit = source.retention - source.bucket - lag
+		}
+	}
+	return limit
+}
+
+// Funcție care întoarce retenția tabelului dat
+func (r metricsRetention) of(table string) time.Duration {
+	switch table {
+	case "metrici_statii":
+		return r.raw
+	case "metrici_statii_1m":
+		return r.minute
+	case "metrici_statii_1h":
+		return r.hour
+	case "metrici_statii_1d":
+		return r.day
+	}
+	return 0
+}
+
+// Coloanele agregate, calculate din eșantioanele brute
+const rawRollupColumns = `COUNT(*),
+	MIN(utilizare_cpu), AVG(utilizare_cpu), MAX(utilizare_cpu),
+	MIN(utilizare_memorie), AVG(utilizare_memorie), MAX(utilizare_memorie),
+	MIN(trafic_retea_bytes_trimisi), MAX(trafic_retea_bytes_trimisi),
+	MIN(trafic_retea_bytes_primiti), MAX(trafic_retea_bytes_primiti)`
+
+// Coloanele agregate, calculate dintr-o rezoluție mai fină; media este ponderată cu numărul de eșantioane
+const rollupRollupColumns = `SUM(esantioane),
+	MIN(utilizare_cpu_min), SUM(utilizare_cpu_medie * esantioane) / NULLIF(SUM(esantioane), 0), MAX(utilizare_cpu_max),
+	MIN(utilizare_memorie_min), SUM(utilizare_memorie_medie * esantioane) / NULLIF(SUM(esantioane), 0), MAX(utilizare_memorie_max),
+	MIN(trafic_retea_bytes_trimisi_min), MAX(trafic_retea_bytes_trimisi_max),
+	MIN(trafic_retea_bytes_primiti_min), MAX(trafic_retea_bytes_primiti_max)`
+
+// Funcție care recalculează intervalele unei rezoluții atinse în această tranzacție
+// touched selectează (id_statie, moment) pentru intervalele atinse; source este
+// tabelul din care se agregă, cu coloana de timp timeColumn
+func rollupBuckets(tx *sql.Tx, target metricRollup, touched, source, timeColumn, columns string, args ...interface{}) (int64, error) {
+	result, err := tx.Exec(fmt.Sprintf(`
+		WITH atinse AS (%s)
+		INSERT INTO %s (id_statie, moment, esantioane,
+			utilizare_cpu_min, utilizare_cpu_medie, utilizare_cpu_max,
+			utilizare_memorie_min, utilizare_memorie_medie, utilizare_memorie_max,
+			trafic_retea_bytes_trimisi_min, trafic_retea_bytes_trimisi_max,
+			trafic_retea_bytes_primiti_min, trafic_retea_bytes_primiti_max, actualizat_la)
+		SELECT s.id_statie, a.moment, %s, LOCALTIMESTAMP
+		FROM atinse a
+		JOIN %s s ON s.id_statie = a.id_statie AND s.%s >= a.moment AND s.%s < a.moment + INTERVAL '1 %s'
+		GROUP BY s.id_statie, a.moment
+		ON CONFLICT (id_statie, moment) DO UPDATE SET
+			esantioane = EXCLUDED.esantioane,
+			utilizare_cpu_min = EXCLUDED.utilizare_cpu_min,
+			utilizare_cpu_medie = EXCLUDED.utilizare_cpu_medie,
+			utilizare_cpu_max = EXCLUDED.utilizare_cpu_max,
+			utilizare_memorie_min = EXCLUDED.utilizare_memorie_min,
+			utilizare_memorie_medie = EXCLUDED.utilizare_memorie_medie,
+			utilizare_memorie_max = EXCLUDED.utilizare_memorie_max,
+			trafic_retea_bytes_trimisi_min = EXCLUDED.trafic_retea_bytes_trimisi_min,
+			trafic_retea_bytes_trimisi_max = EXCLUDED.trafic_retea_bytes_trimisi_max,
+			trafic_retea_bytes_primiti_min = EXCLUDED.trafic_retea_bytes_primiti_min,
+			trafic_retea_bytes_primiti_max = EXCLUDED.trafic_retea_bytes_primiti_max,
+			actualizat_la = EXCLUDED.actualizat_la
+	`, touched, target.table, columns, source, timeColumn, timeColumn, target.unit), args...)
+	if err != nil {
+		return 0, fmt.Errorf("eroare la agregarea în %s: %w", target.table, err)
+	}
+	return result.RowsAffected()
+}
+
+// Funcție care agregă eșantioanele primite de la ultima rulare
+// Toate rezoluțiile sunt actualizate în aceeași tranzacție; rândul de stare
+// blocat cu FOR UPDATE împiedică rularea simultană pe mai multe servere
+func rollupMetrics(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("eroare la începerea tranzacției: %w", err)
+	}
+	defer tx.Rollback()
+
+	var from, to time.Time
+	err = tx.QueryRow(`
+		SELECT procesat_pana_la, LOCALTIMESTAMP - $1 * INTERVAL '1 second'
+		FROM stare_agregare_metrici WHERE nume = 'metrici_statii'
+		FOR UPDATE
+	`, rollupDelay.Seconds()).Scan(&from, &to)
+	if err != nil {
+		return fmt.Errorf("eroare la citirea stării agregării: %w", err)
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	// Minutele atinse de eșantioanele primite între cele două momente
+	minute := metricRollups[0]
+	_, err = rollupBuckets(tx, minute, `
+		SELECT DISTINCT id_statie, date_trunc('minute', timestamp) AS moment
+		FROM metrici_statii WHERE primit_la > $1 AND primit_la <= $2
+	`, "metrici_statii", "timestamp", rawRollupColumns, from, to)
+	if err != nil {
+		return err
+	}
+
+	// Orele și zilele atinse de agregatele actualizate în această tranzacție;
+	// LOCALTIMESTAMP este același pe toată durata tranzacției
+	for i := 1; i < len(metricRollups); i++ {
+		finer, target := metricRollups[i-1], metricRollups[i]
+		_, err = rollupBuckets(tx, target, fmt.Sprintf(`
+			SELECT DISTINCT id_statie, date_trunc('%s', moment) AS moment
+			FROM %s WHERE actualizat_la = LOCALTIMESTAMP
+		`, target.unit, finer.table), finer.table, "moment", rollupRollupColumns)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE stare_agregare_metrici SET procesat_pana_la = $1 WHERE nume = 'metrici_statii'", to)
+	if err != nil {
+		return fmt.Errorf("eroare la salvarea stării agregării: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("eroare la salvarea agregatelor: %w", err)
+	}
+	return nil
+}
+
+// Funcție care șterge metricile mai vechi decât retenția fiecărui tabel
+// Eșantioanele brute sunt șterse pe partiții, doar după ce au fost agregate
+func pruneMetrics(ctx context.Context, db *sql.DB, retention metricsRetention) error {
+	err := dropExpiredMetricsPartitions(ctx, db, retention.raw)
+	if err != nil {
+		return err
+	}
+
+	for _, rollup := range metricRollups {
+		keep := retention.of(rollup.table)
+		if keep <= 0 {
+			continue
+		}
+		result, err := db.ExecContext(ctx, fmt.Sprintf(`
+			DELETE FROM %s WHERE moment < LOCALTIMESTAMP - $1 * INTERVAL '1 second'
+		`, rollup.table), keep.Seconds())
+		if err != nil {
+			return fmt.Errorf("eroare la ștergerea agregatelor vechi din %s: %w", rollup.table, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			fmt.Printf("Retenție: %d rânduri șterse din %s\n", n, rollup.table)
+		}
+	}
+	return nil
+}
+
+// Setările întreținerii periodice a metricilor
+type metricsMaintenance struct {
+	interval  time.Duration
+	retention metricsRetention
+	period    string // perioada unei partiții: zi sau saptamana
+	ahead     int    // numărul de perioade viitoare pentru care se creează partiții
+}
+
+// Funcție care creează partițiile, agregă metricile și aplică retenția la
+// intervalul dat, până la anularea contextului
+func runMetricsMaintenance(ctx context.Context, db *sql.DB, m metricsMaintenance) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		err := ensureMetricsPartitions(ctx, db, m.period, m.ahead)
+		if err != nil {
+			fmt.Printf("Eroare la crearea partițiilor metricilor: %v\n", err)
+		}
+
+		err = rollupMetrics(ctx, db)
+		if err != nil {
+			fmt.Printf("Eroare la agregarea metricilor: %v\n", err)
+		} else {
+			err = pruneMetrics(ctx, db, m.retention)
+			if err != nil {
+				fmt.Printf("Eroare la aplicarea retenției metricilor: %v\n", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
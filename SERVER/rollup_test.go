@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Un eșantion întârziat este acceptat doar cât timp sursa din care este
+// recalculat fiecare agregat al său nu a fost ștearsă
+func TestMetricsRetentionMaxSampleAge(t *testing.T) {
+	day := 24 * time.Hour
+	lag := rollupDelay + time.Minute
+
+	tests := []struct {
+		name      string
+		retention metricsRetention
+		want      time.Duration
+	}{
+		{name: "fără retenție", retention: metricsRetention{}, want: maxSampleAge},
+		{name: "retenții lungi", retention: metricsRetention{raw: 60 * day, minute: 90 * day, hour: 365 * day}, want: maxSampleAge},
+		{name: "limitată de datele brute", retention: metricsRetention{raw: 7 * day, minute: 30 * day, hour: 365 * day}, want: 7*day - time.Minute - lag},
+		{name: "limitată de agregatele pe minut", retention: metricsRetention{raw: 0, minute: 2 * day}, want: 2*day - time.Hour - lag},
+		{name: "limitată de agregatele pe oră", retention: metricsRetention{hour: 10 * day}, want: 10*day - day - lag},
+		// Agregatele zilnice nu sunt sursa niciunui alt agregat
+		{name: "retenția zilnică nu contează", retention: metricsRetention{day: day}, want: maxSampleAge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.retention.maxSampleAge(time.Minute); got != tt.want {
+				t.Errorf("maxSampleAge = %s, așteptat %s", got, tt.want)
+			}
+		})
+	}
+
+	// Coada locală a agenților păstrează implicit 6 zile de eșantioane, care
+	// trebuie să fie încă acceptate cu configurația implicită a serverului
+	if got := defaultServerConfig().maxSampleAge(); got < 6*day {
+		t.Errorf("vârsta maximă implicită %s este sub vârsta maximă a cozii agenților", got)
+	}
+}
@@ -2,7 +2,10 @@ module Cpu
 
 go 1.22.4
 
-require github.com/shirou/gopsutil v3.21.11+incompatible
+require (
+	github.com/lib/pq v1.10.9
+	github.com/shirou/gopsutil v3.21.11+incompatible
+)
 
 require (
 	github.com/bytedance/sonic v1.11.6 // indirect
@@ -20,7 +23,6 @@ require (
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/klauspost/cpuid/v2 v2.2.7 // indirect
 	github.com/leodido/go-urn v1.4.0 // indirect
-	github.com/lib/pq v1.10.9 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
@@ -40,3 +42,7 @@ require (
 	google.golang.org/protobuf v1.34.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+require colector v0.0.0
+
+replace colector => ../COLECTOR